@@ -0,0 +1,3 @@
+module github.com/henryliu5/convex-hull
+
+go 1.21
@@ -0,0 +1,514 @@
+package hull
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"unsafe"
+)
+
+/***********************
+ * Binary point format *
+ ***********************
+Text parsing dominates the end-to-end time on large inputs, so points can also be stored as raw
+little-endian coordinates behind a fixed 16 byte header:
+
+	offset 0   [4]byte  magic "CHPT"
+	offset 4   uint16   format version (1)
+	offset 6   uint8    dimension (2 to MaxDimension)
+	offset 7   uint8    coordinate type (1 float32, 2 float64, 3 int32, 4 int64)
+	offset 8   uint64   number of points
+	offset 16           count*dimension coordinates, x, y (z, ...) for each point
+
+The header keeps the data 8 byte aligned, so a memory mapped file can be used in place.
+*/
+
+// BinaryMagic starts every binary point file
+const BinaryMagic = "CHPT"
+
+// Current binary format version
+const BinaryVersion = 1
+
+// Size of the binary header in bytes
+const binary_header_size = 16
+
+// CoordType is the type of the coordinates stored in a binary point file
+type CoordType uint8
+
+const (
+	CoordFloat32 CoordType = iota + 1
+	CoordFloat64
+	CoordInt32
+	CoordInt64
+)
+
+// Size of one coordinate in bytes, 0 for unknown types
+func (c CoordType) Size() int {
+	switch c {
+	case CoordFloat32, CoordInt32:
+		return 4
+	case CoordFloat64, CoordInt64:
+		return 8
+	}
+	return 0
+}
+
+func (c CoordType) String() string {
+	switch c {
+	case CoordFloat32:
+		return "float32"
+	case CoordFloat64:
+		return "float64"
+	case CoordInt32:
+		return "int32"
+	case CoordInt64:
+		return "int64"
+	}
+	return fmt.Sprintf("CoordType(%d)", uint8(c))
+}
+
+// Coordinate type of T
+func coord_type[T Coord]() CoordType {
+	switch any(*new(T)).(type) {
+	case float32:
+		return CoordFloat32
+	case float64:
+		return CoordFloat64
+	case int32:
+		return CoordInt32
+	}
+	return CoordInt64
+}
+
+// Whether T is a floating point type, which can hold NaN and infinities
+func is_float[T Coord]() bool {
+	c := coord_type[T]()
+	return c == CoordFloat32 || c == CoordFloat64
+}
+
+// BinaryHeader describes the contents of a binary point file
+type BinaryHeader struct {
+	Version   uint16
+	Dimension uint8
+	Coord     CoordType
+	Count     uint64
+}
+
+// ErrNotBinary is returned for input that does not start with BinaryMagic
+var ErrNotBinary = errors.New("hull: not a binary point file")
+
+// ParseBinaryHeader decodes and checks the header at the start of a binary point file
+func ParseBinaryHeader(b []byte) (BinaryHeader, error) {
+	if len(b) < binary_header_size || string(b[:4]) != BinaryMagic {
+		return BinaryHeader{}, ErrNotBinary
+	}
+	header := BinaryHeader{
+		Version:   binary.LittleEndian.Uint16(b[4:]),
+		Dimension: b[6],
+		Coord:     CoordType(b[7]),
+		Count:     binary.LittleEndian.Uint64(b[8:]),
+	}
+	if header.Version != BinaryVersion {
+		return header, fmt.Errorf("hull: unsupported binary format version %d", header.Version)
+	}
+	if header.Dimension < 2 || header.Dimension > MaxDimension {
+		return header, fmt.Errorf("hull: unsupported dimension %d", header.Dimension)
+	}
+	if header.Coord.Size() == 0 {
+		return header, fmt.Errorf("hull: unsupported coordinate type %v", header.Coord)
+	}
+	return header, nil
+}
+
+// Decode a header for points read as [dim]T
+func parse_header[T Coord](b []byte, dim int) (BinaryHeader, error) {
+	header, err := ParseBinaryHeader(b)
+	if err != nil {
+		return header, err
+	}
+	if int(header.Dimension) != dim {
+		return header, fmt.Errorf("hull: file has %d dimensional points, expected %d", header.Dimension, dim)
+	}
+	if header.Coord != coord_type[T]() {
+		return header, fmt.Errorf("hull: file has %v coordinates, expected %v", header.Coord, coord_type[T]())
+	}
+	return header, nil
+}
+
+// Encode a header for count [dim]T points
+func make_header[T Coord](count int, dim int) []byte {
+	b := make([]byte, binary_header_size)
+	copy(b, BinaryMagic)
+	binary.LittleEndian.PutUint16(b[4:], BinaryVersion)
+	b[6] = uint8(dim)
+	b[7] = uint8(coord_type[T]())
+	binary.LittleEndian.PutUint64(b[8:], uint64(count))
+	return b
+}
+
+// Whether this machine stores numbers little-endian, so file data can be used as is
+var native_little_endian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// Decode a little-endian coordinate from the start of b
+func decode_coord[T Coord](b []byte) T {
+	switch coord_type[T]() {
+	case CoordFloat32:
+		return T(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	case CoordFloat64:
+		return T(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+	case CoordInt32:
+		return T(int32(binary.LittleEndian.Uint32(b)))
+	}
+	return T(int64(binary.LittleEndian.Uint64(b)))
+}
+
+// Encode x little-endian at the start of b
+func encode_coord[T Coord](b []byte, x T) {
+	switch coord_type[T]() {
+	case CoordFloat32:
+		binary.LittleEndian.PutUint32(b, math.Float32bits(float32(x)))
+	case CoordFloat64:
+		binary.LittleEndian.PutUint64(b, math.Float64bits(float64(x)))
+	case CoordInt32:
+		binary.LittleEndian.PutUint32(b, uint32(int32(x)))
+	default:
+		binary.LittleEndian.PutUint64(b, uint64(int64(x)))
+	}
+}
+
+// Decode little-endian coordinates from b into coords
+func decode_coords[T Coord](b []byte, coords []T) {
+	size := coord_type[T]().Size()
+	for i := range coords {
+		coords[i] = decode_coord[T](b[size*i:])
+	}
+}
+
+// View coords as consecutive points of dim coordinates, P must be [dim]T
+func points_of[P any, T Coord](coords []T, dim int) []P {
+	if len(coords) == 0 {
+		return []P{}
+	}
+	return unsafe.Slice((*P)(unsafe.Pointer(&coords[0])), len(coords)/dim)
+}
+
+// View points, which must be [dim]T, as their consecutive coordinates
+func coords_of[T Coord, P any](points []P, dim int) []T {
+	if len(points) == 0 {
+		return nil
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(&points[0])), len(points)*dim)
+}
+
+// Split coords into consecutive points of dim coordinates, sharing its storage
+func split_points[T Coord](coords []T, dim int) [][]T {
+	points := make([][]T, len(coords)/dim)
+	for i := range points {
+		points[i] = coords[i*dim : (i+1)*dim : (i+1)*dim]
+	}
+	return points
+}
+
+// Consecutive coordinates of points, which must all have dim coordinates
+func join_points[T Coord](points [][]T, dim int) ([]T, error) {
+	coords := make([]T, 0, len(points)*dim)
+	for i, p := range points {
+		if len(p) != dim {
+			return nil, fmt.Errorf("hull: point %d has %d coordinates, expected %d", i, len(p), dim)
+		}
+		coords = append(coords, p...)
+	}
+	return coords, nil
+}
+
+// Points with NaN or infinite coordinates break the orientation tests, reject them like the text parser
+func check_finite[T Coord](coords []T, dim int) error {
+	if !is_float[T]() {
+		return nil
+	}
+	for i, x := range coords {
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return fmt.Errorf("hull: point %d: %w", i/dim, ErrNotFinite)
+		}
+	}
+	return nil
+}
+
+// ReadBinary reads a binary point file with T coordinates from r
+func ReadBinary[T Coord](r io.Reader) ([][2]T, error) {
+	coords, err := read_binary[T](r, 2)
+	if err != nil {
+		return nil, err
+	}
+	return points_of[[2]T](coords, 2), nil
+}
+
+// ReadBinary3D reads a binary point file with 3 dimensional T points from r
+func ReadBinary3D[T Coord](r io.Reader) ([][3]T, error) {
+	coords, err := read_binary[T](r, 3)
+	if err != nil {
+		return nil, err
+	}
+	return points_of[[3]T](coords, 3), nil
+}
+
+// ReadBinaryD reads a binary point file with dim dimensional T points from r
+func ReadBinaryD[T Coord](r io.Reader, dim int) ([][]T, error) {
+	coords, err := read_binary[T](r, dim)
+	if err != nil {
+		return nil, err
+	}
+	return split_points(coords, dim), nil
+}
+
+// Read the coordinates of a binary point file with dim dimensional T points
+func read_binary[T Coord](r io.Reader, dim int) ([]T, error) {
+	b := make([]byte, binary_header_size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrNotBinary
+		}
+		return nil, err
+	}
+	header, err := parse_header[T](b, dim)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read in blocks rather than trusting count for one huge allocation
+	const block_points = 1 << 16
+	point_size := uint64(dim * header.Coord.Size())
+	coords := make([]T, 0, min(header.Count, block_points)*uint64(dim))
+	block := make([]byte, point_size*block_points)
+	for remaining := header.Count; remaining > 0; {
+		n := min(remaining, block_points)
+		if _, err := io.ReadFull(r, block[:point_size*n]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("hull: reading %d points: %w", header.Count, err)
+		}
+		start := len(coords)
+		coords = append(coords, make([]T, n*uint64(dim))...)
+		decode_coords(block, coords[start:])
+		remaining -= n
+	}
+	if err := check_finite(coords, dim); err != nil {
+		return nil, err
+	}
+	return coords, nil
+}
+
+// WriteBinary writes points to w in the binary point format
+func WriteBinary[T Coord](w io.Writer, points [][2]T) error {
+	return write_binary(w, coords_of[T](points, 2), 2)
+}
+
+// WriteBinary3D writes 3 dimensional points to w in the binary point format
+func WriteBinary3D[T Coord](w io.Writer, points [][3]T) error {
+	return write_binary(w, coords_of[T](points, 3), 3)
+}
+
+// WriteBinaryD writes points with dim coordinates each to w in the binary point format
+func WriteBinaryD[T Coord](w io.Writer, dim int, points [][]T) error {
+	if dim < 2 || dim > MaxDimension {
+		return fmt.Errorf("hull: unsupported dimension %d", dim)
+	}
+	coords, err := join_points(points, dim)
+	if err != nil {
+		return err
+	}
+	return write_binary(w, coords, dim)
+}
+
+// Write the coordinates of dim dimensional points in the binary point format
+func write_binary[T Coord](w io.Writer, coords []T, dim int) error {
+	bw := bufio.NewWriterSize(w, 1<<16)
+	bw.Write(make_header[T](len(coords)/dim, dim))
+	coord := make([]byte, coord_type[T]().Size())
+	for _, x := range coords {
+		encode_coord(coord, x)
+		bw.Write(coord)
+	}
+	return bw.Flush()
+}
+
+// WriteBinaryFile writes points to filename in the binary point format
+func WriteBinaryFile[T Coord](filename string, points [][2]T) error {
+	return write_binary_file(filename, func(w io.Writer) error {
+		return WriteBinary(w, points)
+	})
+}
+
+// WriteBinaryFile3D writes 3 dimensional points to filename in the binary point format
+func WriteBinaryFile3D[T Coord](filename string, points [][3]T) error {
+	return write_binary_file(filename, func(w io.Writer) error {
+		return WriteBinary3D(w, points)
+	})
+}
+
+// WriteBinaryFileD writes points with dim coordinates each to filename in the binary point format
+func WriteBinaryFileD[T Coord](filename string, dim int, points [][]T) error {
+	return write_binary_file(filename, func(w io.Writer) error {
+		return WriteBinaryD(w, dim, points)
+	})
+}
+
+// Create filename and fill it with write
+func write_binary_file(filename string, write func(w io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// PointFile is a binary point file opened with OpenBinary
+type PointFile[T Coord] struct {
+	// Points may be backed by a private (copy-on-write) memory mapping of the file,
+	// so they can be modified but must not be used after Close
+	Points [][2]T
+	unmap  func() error
+}
+
+// Close releases the file's memory mapping, if any
+func (f *PointFile[T]) Close() error {
+	f.Points = nil
+	return release(&f.unmap)
+}
+
+// PointFile3D is a binary point file of 3 dimensional points opened with OpenBinary3D, see PointFile
+type PointFile3D[T Coord] struct {
+	Points [][3]T
+	unmap  func() error
+}
+
+// Close releases the file's memory mapping, if any
+func (f *PointFile3D[T]) Close() error {
+	f.Points = nil
+	return release(&f.unmap)
+}
+
+// PointFileD is a binary point file of points with any number of coordinates opened with
+// OpenBinaryD, see PointFile. Each point is a slice of the mapped coordinates.
+type PointFileD[T Coord] struct {
+	Points [][]T
+	unmap  func() error
+}
+
+// Close releases the file's memory mapping, if any
+func (f *PointFileD[T]) Close() error {
+	f.Points = nil
+	return release(&f.unmap)
+}
+
+// Call unmap unless it is nil or already called
+func release(unmap *func() error) error {
+	if *unmap == nil {
+		return nil
+	}
+	f := *unmap
+	*unmap = nil
+	return f()
+}
+
+// OpenBinary opens a binary point file with T coordinates. Where supported the file is memory mapped and, on little-endian
+// machines, its points are used in place without copying.
+func OpenBinary[T Coord](filename string) (*PointFile[T], error) {
+	coords, unmap, err := open_binary[T](filename, 2)
+	if err != nil {
+		return nil, err
+	}
+	return &PointFile[T]{points_of[[2]T](coords, 2), unmap}, nil
+}
+
+// OpenBinary3D opens a binary point file with 3 dimensional T points, like OpenBinary
+func OpenBinary3D[T Coord](filename string) (*PointFile3D[T], error) {
+	coords, unmap, err := open_binary[T](filename, 3)
+	if err != nil {
+		return nil, err
+	}
+	return &PointFile3D[T]{points_of[[3]T](coords, 3), unmap}, nil
+}
+
+// OpenBinaryD opens a binary point file with dim dimensional T points, like OpenBinary
+func OpenBinaryD[T Coord](filename string, dim int) (*PointFileD[T], error) {
+	coords, unmap, err := open_binary[T](filename, dim)
+	if err != nil {
+		return nil, err
+	}
+	return &PointFileD[T]{split_points(coords, dim), unmap}, nil
+}
+
+// Memory map a binary point file with dim dimensional T points and return its coordinates, and the
+// function to unmap them if they are used in place (nil if they were copied)
+func open_binary[T Coord](filename string, dim int) ([]T, func() error, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() < binary_header_size {
+		return nil, nil, fmt.Errorf("%s: %w", filename, ErrNotBinary)
+	}
+
+	data, unmap, err := map_file(file, int(info.Size()))
+	if err != nil {
+		return nil, nil, err
+	}
+	coords, err := binary_coords[T](data, dim)
+	if unmap != nil && (err != nil || !native_little_endian) {
+		// Points were copied out of the mapping, or could not be read
+		unmap()
+		unmap = nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return coords, unmap, nil
+}
+
+// Coordinates of an in-memory binary point file with dim dimensional points, aliasing data on
+// little-endian machines
+func binary_coords[T Coord](data []byte, dim int) ([]T, error) {
+	header, err := parse_header[T](data, dim)
+	if err != nil {
+		return nil, err
+	}
+	body := data[binary_header_size:]
+	point_size := uint64(dim * header.Coord.Size())
+	if header.Count > uint64(len(body))/point_size || uint64(len(body)) != point_size*header.Count {
+		return nil, fmt.Errorf("hull: header has %d points but file holds %d bytes of coordinates", header.Count, len(body))
+	}
+	if header.Count == 0 {
+		return []T{}, nil
+	}
+
+	var coords []T
+	n := int(header.Count) * dim
+	if native_little_endian {
+		coords = unsafe.Slice((*T)(unsafe.Pointer(&body[0])), n)
+	} else {
+		coords = make([]T, n)
+		decode_coords(body, coords)
+	}
+	if err := check_finite(coords, dim); err != nil {
+		return nil, err
+	}
+	return coords, nil
+}
@@ -0,0 +1,227 @@
+package hull
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Whether filename is memory mapped by this process, false if the mappings can't be listed
+func mapped(t *testing.T, filename string) bool {
+	t.Helper()
+	maps, err := os.ReadFile("/proc/self/maps")
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(maps), filename)
+}
+
+func random_points[T Coord](r *rand.Rand, n int) [][2]T {
+	points := make([][2]T, n)
+	for i := range points {
+		points[i] = [2]T{T(r.Intn(2000) - 1000), T(r.Intn(2000) - 1000)}
+		if is_float[T]() {
+			points[i][0] += T(r.Float64())
+			points[i][1] -= T(r.Float64())
+		}
+	}
+	return points
+}
+
+func check_binary_round_trip[T Coord](t *testing.T, r *rand.Rand) {
+	t.Helper()
+	for _, n := range []int{0, 1, 1000} {
+		points := random_points[T](r, n)
+		filename := filepath.Join(t.TempDir(), "points.chpt")
+		if err := WriteBinaryFile(filename, points); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := OpenBinary[T](filename)
+		if err != nil {
+			t.Fatalf("%v %d points: %v", coord_type[T](), n, err)
+		}
+		if !slices.Equal(f.Points, points) {
+			t.Fatalf("%v %d points: read back different points", coord_type[T](), n)
+		}
+		// Writes to the points stay private to the process
+		if n > 0 {
+			f.Points[0][0]++
+			g, err := OpenBinary[T](filename)
+			if err != nil || g.Points[0] != points[0] {
+				t.Fatalf("%v: writing to the points changed the file", coord_type[T]())
+			}
+			g.Close()
+		}
+
+		if _, err := os.Stat("/proc/self/maps"); err == nil && native_little_endian && n > 0 && !mapped(t, filename) {
+			t.Fatalf("%v %d points: file is not memory mapped", coord_type[T](), n)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if f.Points != nil || mapped(t, filename) {
+			t.Fatalf("%v %d points: still mapped after Close", coord_type[T](), n)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatalf("second Close: %v", err)
+		}
+
+		// Streamed round trip
+		var buf bytes.Buffer
+		if err := WriteBinary(&buf, points); err != nil {
+			t.Fatal(err)
+		}
+		read, err := ReadBinary[T](&buf)
+		if err != nil || !slices.Equal(read, points) {
+			t.Fatalf("%v %d points: ReadBinary got %d points, %v", coord_type[T](), n, len(read), err)
+		}
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	check_binary_round_trip[float32](t, r)
+	check_binary_round_trip[float64](t, r)
+	check_binary_round_trip[int32](t, r)
+	check_binary_round_trip[int64](t, r)
+}
+
+func TestOpenBinaryErrors(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "points.chpt")
+	if err := WriteBinaryFile(filename, [][2]float64{{1, 2}, {3, 4}, {5, 6}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bad_magic := slices.Clone(data)
+	copy(bad_magic, "CHPX")
+	bad_version := slices.Clone(data)
+	bad_version[4] = 9
+	tests := []struct {
+		name string
+		data []byte
+		// Expected error, nil to only check that there is one
+		err error
+	}{
+		{"empty", nil, ErrNotBinary},
+		{"short header", data[:10], ErrNotBinary},
+		{"bad magic", bad_magic, ErrNotBinary},
+		{"bad version", bad_version, nil},
+		{"truncated", data[:len(data)-3], nil},
+		{"truncated point", data[:len(data)-16], nil},
+		{"trailing bytes", append(slices.Clone(data), 0), nil},
+	}
+	for _, test := range tests {
+		bad := filepath.Join(dir, "bad.chpt")
+		if err := os.WriteFile(bad, test.data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		f, err := OpenBinary[float64](bad)
+		if err == nil {
+			f.Close()
+			t.Fatalf("%s: opened without an error", test.name)
+		}
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Fatalf("%s: got %v, want %v", test.name, err, test.err)
+		}
+		if mapped(t, bad) {
+			t.Fatalf("%s: still mapped after the error", test.name)
+		}
+		if _, err := ReadBinary[float64](bytes.NewReader(test.data)); err == nil && test.name != "trailing bytes" {
+			t.Fatalf("%s: ReadBinary read it without an error", test.name)
+		}
+	}
+
+	// The header has to match the points asked for
+	if _, err := OpenBinary[float32](filename); err == nil {
+		t.Fatal("opened float64 points as float32")
+	}
+	if _, err := ReadBinary3D[float64](bytes.NewReader(data)); err == nil {
+		t.Fatal("read 2D points as 3D")
+	}
+}
+
+func TestOpenBinary3D(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, n := range []int{0, 1, 500} {
+		points := make([][3]int32, n)
+		for i := range points {
+			points[i] = [3]int32{int32(r.Intn(100)), int32(r.Intn(100)), int32(r.Intn(100))}
+		}
+		filename := filepath.Join(t.TempDir(), "points.chpt")
+		if err := WriteBinaryFile3D(filename, points); err != nil {
+			t.Fatal(err)
+		}
+		f, err := OpenBinary3D[int32](filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(f.Points, points) {
+			t.Fatalf("%d points: read back different points", n)
+		}
+		if _, err := os.Stat("/proc/self/maps"); err == nil && native_little_endian && n > 0 && !mapped(t, filename) {
+			t.Fatalf("%d points: file is not memory mapped", n)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if f.Points != nil || mapped(t, filename) {
+			t.Fatalf("%d points: still mapped after Close", n)
+		}
+	}
+
+	// 2D files are not 3D files
+	filename := filepath.Join(t.TempDir(), "points.chpt")
+	if err := WriteBinaryFile(filename, [][2]int32{{1, 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenBinary3D[int32](filename); err == nil {
+		t.Fatal("opened 2D points as 3D")
+	}
+}
+
+func TestOpenBinaryD(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for _, dim := range []int{2, 5, MaxDimension} {
+		points := make([][]float64, 300)
+		for i := range points {
+			points[i] = make([]float64, dim)
+			for j := range points[i] {
+				points[i][j] = r.NormFloat64()
+			}
+		}
+		filename := filepath.Join(t.TempDir(), "points.chpt")
+		if err := WriteBinaryFileD(filename, dim, points); err != nil {
+			t.Fatal(err)
+		}
+		f, err := OpenBinaryD[float64](filename, dim)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.EqualFunc(f.Points, points, slices.Equal[[]float64]) {
+			t.Fatalf("dim %d: read back different points", dim)
+		}
+		if _, err := os.Stat("/proc/self/maps"); err == nil && native_little_endian && !mapped(t, filename) {
+			t.Fatalf("dim %d: file is not memory mapped", dim)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if f.Points != nil || mapped(t, filename) {
+			t.Fatalf("dim %d: still mapped after Close", dim)
+		}
+		if _, err := OpenBinaryD[float64](filename, dim+1); err == nil {
+			t.Fatalf("opened %d dimensional points as %d dimensional", dim, dim+1)
+		}
+	}
+}
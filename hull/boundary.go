@@ -0,0 +1,112 @@
+package hull
+
+import "sort"
+
+// Where a point lies relative to a convex polygon
+const (
+	outside  = -1
+	boundary = 0
+	inside   = 1
+)
+
+// Locate p relative to a counterclockwise, strictly convex hull with at least 3 vertices in O(log h)
+// by binary searching the fan of triangles around hull[0]. Returns outside, boundary or inside, and
+// for boundary points the index i of the edge hull[i]->hull[i+1] that p lies on.
+func locate[T Coord](hull [][2]T, p [2]T) (int, int) {
+	h := len(hull)
+	v0 := hull[0]
+
+	// Outside the angle at v0
+	first := orient(v0, hull[1], p)
+	last := orient(v0, hull[h-1], p)
+	if first < 0 || last > 0 {
+		return outside, -1
+	}
+	// On the line through one of the edges at v0, boundary unless past the far end
+	if first == 0 {
+		if along(v0, hull[1], hull[1], p) <= 0 {
+			return boundary, 0
+		}
+		return outside, -1
+	}
+	if last == 0 {
+		if along(v0, hull[h-1], hull[h-1], p) <= 0 {
+			return boundary, h - 1
+		}
+		return outside, -1
+	}
+
+	// Find the wedge hull[0], hull[lo], hull[lo+1] containing p
+	lo, hi := 1, h-1
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if orient(v0, hull[mid], p) >= 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	switch orient(hull[lo], hull[lo+1], p) {
+	case 1:
+		return inside, -1
+	case 0:
+		return boundary, lo
+	}
+	return outside, -1
+}
+
+// Every distinct point of points on the boundary of hull (canonical counterclockwise, strict),
+// in counterclockwise order starting from hull[0]
+func boundary_points[T Coord](points [][2]T, hull [][2]T) [][2]T {
+	h := len(hull)
+	if h < 3 {
+		if h < 2 {
+			return hull
+		}
+		// All points are on the segment hull, order them from hull[0]
+		res := make([][2]T, len(points))
+		copy(res, points)
+		sort.Slice(res, func(i, j int) bool {
+			return along(hull[0], hull[1], res[i], res[j]) > 0
+		})
+		return dedupe_sorted(res)
+	}
+
+	type on_edge struct {
+		point [2]T
+		edge  int
+	}
+	var found []on_edge
+	for _, p := range points {
+		if loc, edge := locate(hull, p); loc == boundary {
+			found = append(found, on_edge{p, edge})
+		}
+	}
+
+	// Order by edge, then by position along the edge
+	sort.Slice(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.edge != b.edge {
+			return a.edge < b.edge
+		}
+		return along(hull[a.edge], hull[(a.edge+1)%h], a.point, b.point) > 0
+	})
+
+	res := make([][2]T, len(found))
+	for i, f := range found {
+		res[i] = f.point
+	}
+	return dedupe_sorted(res)
+}
+
+// Remove adjacent duplicates in place
+func dedupe_sorted[T Coord](points [][2]T) [][2]T {
+	unique := 0
+	for i, p := range points {
+		if i == 0 || p != points[unique-1] {
+			points[unique] = p
+			unique++
+		}
+	}
+	return points[:unique]
+}
@@ -0,0 +1,261 @@
+package hull
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+)
+
+/**********************
+ * Rotating calipers  *
+ **********************
+Measures of a convex polygon from one walk around it. For each edge, three more pointers follow the
+vertex farthest from the edge's line and the vertices furthest forward and backward along it. As the
+edge turns counterclockwise each of these only moves forward, so all of them together go around the
+polygon a bounded number of times and the walk is O(h).
+
+The farthest pair is antipodal, and every antipodal pair is seen while the far pointer advances. The
+minimum width is the distance from some edge to its far vertex, and the minimum-area and
+minimum-perimeter enclosing rectangles each have a side flush with an edge (Freeman and Shapira,
+Toussaint), so it is enough to try one rectangle per edge.
+
+Pointers advance on exact signs of cross and dot products, the measures themselves are float64.
+*/
+
+var (
+	ErrEmptyPolygon = errors.New("hull: polygon has no vertices")
+	ErrNotConvex    = errors.New("hull: polygon is not convex")
+)
+
+// Rectangle enclosing a polygon with one side flush with a polygon edge
+type Rectangle struct {
+	// Counterclockwise, Corners[0]->Corners[1] lies along the edge
+	Corners   [4][2]float64
+	Area      float64
+	Perimeter float64
+	// Polygon edge Edge[0]->Edge[1] lying on the first side
+	Edge [2]int
+	// Vertex touching each side, in the order of the sides (Support[0] is Edge[0])
+	Support [4]int
+}
+
+// Calipers are rotating-calipers measures of a convex polygon. Vertex indices refer to the polygon
+// that was measured.
+type Calipers struct {
+	// Largest distance between two vertices, and the vertices
+	Diameter     float64
+	DiameterPair [2]int
+	// Smallest distance between two parallel lines enclosing the polygon. One line goes through
+	// the edge WidthEdge[0]->WidthEdge[1], the other through WidthVertex.
+	Width       float64
+	WidthEdge   [2]int
+	WidthVertex int
+	// Smallest enclosing rectangles by area and by perimeter
+	MinArea      Rectangle
+	MinPerimeter Rectangle
+}
+
+// RotatingCalipers measures a convex polygon given in order, clockwise or counterclockwise, such as
+// the output of any of the hull algorithms. Collinear boundary points and repeated vertices are
+// allowed. Fewer than 3 distinct vertices, or all of them on a line, measure as a segment with width
+// 0 and a degenerate rectangle. O(h)
+func RotatingCalipers[T Coord](polygon [][2]T) (*Calipers, error) {
+	if len(polygon) == 0 {
+		return nil, ErrEmptyPolygon
+	}
+	vertices, err := convex_vertices(polygon)
+	if err != nil {
+		return nil, err
+	}
+	if len(vertices) < 3 {
+		return segment_calipers(polygon, vertices), nil
+	}
+
+	n := len(vertices)
+	q := make([][2]T, n)
+	for i, v := range vertices {
+		q[i] = polygon[v]
+	}
+	at := func(i int) [2]T { return q[mod(i, n)] }
+
+	res := &Calipers{Width: math.Inf(1)}
+	res.MinArea.Area = math.Inf(1)
+	res.MinPerimeter.Perimeter = math.Inf(1)
+
+	// far is the vertex farthest from the line of edge i, ahead the one furthest along it and
+	// behind the one furthest back. They are unbounded, reduced mod n on use.
+	far, ahead, behind := 1, 1, 1
+	for i := 0; i < n; i++ {
+		a, b := q[i], at(i+1)
+		// Every vertex passed is antipodal to a
+		for {
+			if d := dist(a, at(far)); d > res.Diameter {
+				res.Diameter = d
+				res.DiameterPair = [2]int{vertices[i], vertices[mod(far, n)]}
+			}
+			if cross_sign(a, b, at(far), at(far+1)) <= 0 {
+				break
+			}
+			far++
+		}
+		for dot_sign(a, b, at(ahead), at(ahead+1)) > 0 {
+			ahead++
+		}
+		if i == 0 {
+			behind = far
+		}
+		for dot_sign(a, b, at(behind), at(behind+1)) < 0 {
+			behind++
+		}
+
+		// Frame of the edge: unit vector u along it, height measured to its left
+		ux, uy := float64(b[0])-float64(a[0]), float64(b[1])-float64(a[1])
+		length := math.Hypot(ux, uy)
+		ux, uy = ux/length, uy/length
+		offset := func(p [2]T) (float64, float64) {
+			px, py := float64(p[0])-float64(a[0]), float64(p[1])-float64(a[1])
+			return px*ux + py*uy, px*-uy + py*ux
+		}
+		_, height := offset(at(far))
+		start, _ := offset(at(behind))
+		end, _ := offset(at(ahead))
+
+		edge := [2]int{vertices[i], vertices[mod(i+1, n)]}
+		if height < res.Width {
+			res.Width = height
+			res.WidthEdge = edge
+			res.WidthVertex = vertices[mod(far, n)]
+		}
+		area, perimeter := (end-start)*height, 2*(end-start+height)
+		if area < res.MinArea.Area || perimeter < res.MinPerimeter.Perimeter {
+			ax, ay := float64(a[0]), float64(a[1])
+			corner := func(s, t float64) [2]float64 {
+				return [2]float64{ax + s*ux - t*uy, ay + s*uy + t*ux}
+			}
+			rect := Rectangle{
+				Corners:   [4][2]float64{corner(start, 0), corner(end, 0), corner(end, height), corner(start, height)},
+				Area:      area,
+				Perimeter: perimeter,
+				Edge:      edge,
+				Support:   [4]int{edge[0], vertices[mod(ahead, n)], vertices[mod(far, n)], vertices[mod(behind, n)]},
+			}
+			if area < res.MinArea.Area {
+				res.MinArea = rect
+			}
+			if perimeter < res.MinPerimeter.Perimeter {
+				res.MinPerimeter = rect
+			}
+		}
+	}
+	return res, nil
+}
+
+// Indices of the strictly convex vertices of polygon, counterclockwise. Repeated vertices and
+// points in the middle of an edge are dropped. Fewer than 3 indices are returned for a polygon
+// whose vertices are all on a line: its two extreme points, or one if they are all the same.
+func convex_vertices[T Coord](polygon [][2]T) ([]int, error) {
+	// Drop repeats of the previous vertex (cyclically)
+	var distinct []int
+	for i, p := range polygon {
+		if len(distinct) == 0 || p != polygon[distinct[len(distinct)-1]] {
+			distinct = append(distinct, i)
+		}
+	}
+	for len(distinct) > 1 && polygon[distinct[len(distinct)-1]] == polygon[distinct[0]] {
+		distinct = distinct[:len(distinct)-1]
+	}
+
+	n := len(distinct)
+	var res []int
+	turn := 0
+	turned_back := false
+	for i, v := range distinct {
+		prev, next := polygon[distinct[mod(i+n-1, n)]], polygon[distinct[mod(i+1, n)]]
+		switch o := orient(prev, polygon[v], next); {
+		case o == 0:
+			// Straight through is fine, turning back is only allowed if everything is on a line
+			if n > 2 {
+				if along(prev, polygon[v], polygon[v], next) > 0 {
+					continue
+				}
+				turned_back = true
+			}
+		case turn == 0:
+			turn = o
+		case o != turn:
+			return nil, ErrNotConvex
+		}
+		res = append(res, v)
+	}
+	if turn == 0 {
+		return line_extremes(polygon, distinct), nil
+	}
+	if turned_back {
+		return nil, ErrNotConvex
+	}
+
+	// Turning the same way at every vertex, the polygon is convex if it winds around once, which
+	// is when it has a single lexicographic minimum
+	lex := func(p, q [2]T) int {
+		if c := cmp.Compare(p[0], q[0]); c != 0 {
+			return c
+		}
+		return cmp.Compare(p[1], q[1])
+	}
+	minima := 0
+	for i, v := range res {
+		p := polygon[v]
+		if lex(p, polygon[res[mod(i+len(res)-1, len(res))]]) < 0 && lex(p, polygon[res[mod(i+1, len(res))]]) < 0 {
+			minima++
+		}
+	}
+	if minima != 1 || len(res) < 3 {
+		return nil, ErrNotConvex
+	}
+	if turn < 0 {
+		slices.Reverse(res)
+	}
+	return res, nil
+}
+
+// Lexicographically smallest and largest of the points at indices, which are all on a line
+func line_extremes[T Coord](polygon [][2]T, indices []int) []int {
+	lo, hi := indices[0], indices[0]
+	for _, i := range indices {
+		p := polygon[i]
+		if p[0] < polygon[lo][0] || (p[0] == polygon[lo][0] && p[1] < polygon[lo][1]) {
+			lo = i
+		}
+		if p[0] > polygon[hi][0] || (p[0] == polygon[hi][0] && p[1] > polygon[hi][1]) {
+			hi = i
+		}
+	}
+	if lo == hi {
+		return []int{lo}
+	}
+	return []int{lo, hi}
+}
+
+// Measures of a polygon with no area, from the one or two extreme vertices of line_extremes
+func segment_calipers[T Coord](polygon [][2]T, vertices []int) *Calipers {
+	a, b := vertices[0], vertices[len(vertices)-1]
+	pa, pb := polygon[a], polygon[b]
+	corner_a := [2]float64{float64(pa[0]), float64(pa[1])}
+	corner_b := [2]float64{float64(pb[0]), float64(pb[1])}
+	length := dist(pa, pb)
+	rect := Rectangle{
+		Corners:   [4][2]float64{corner_a, corner_b, corner_b, corner_a},
+		Perimeter: 2 * length,
+		Edge:      [2]int{a, b},
+		Support:   [4]int{a, b, a, a},
+	}
+	return &Calipers{
+		Diameter:     length,
+		DiameterPair: [2]int{a, b},
+		WidthEdge:    [2]int{a, b},
+		WidthVertex:  a,
+		MinArea:      rect,
+		MinPerimeter: rect,
+	}
+}
@@ -0,0 +1,190 @@
+package hull
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Brute-force measures of polygon: the farthest pair of vertices, and over every edge the
+// distance to the farthest vertex from its line and the rectangle flush with it
+func brute_force_calipers[T Coord](polygon [][2]T) (diameter, width, area, perimeter float64) {
+	width, area, perimeter = math.Inf(1), math.Inf(1), math.Inf(1)
+	for i, a := range polygon {
+		for _, b := range polygon[i+1:] {
+			diameter = math.Max(diameter, dist(a, b))
+		}
+	}
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		ux, uy := float64(b[0])-float64(a[0]), float64(b[1])-float64(a[1])
+		length := math.Hypot(ux, uy)
+		if length == 0 {
+			continue
+		}
+		ux, uy = ux/length, uy/length
+		lo, hi, height := math.Inf(1), math.Inf(-1), 0.0
+		for _, p := range polygon {
+			px, py := float64(p[0])-float64(a[0]), float64(p[1])-float64(a[1])
+			s, t := px*ux+py*uy, math.Abs(px*-uy+py*ux)
+			lo, hi, height = math.Min(lo, s), math.Max(hi, s), math.Max(height, t)
+		}
+		width = math.Min(width, height)
+		area = math.Min(area, (hi-lo)*height)
+		perimeter = math.Min(perimeter, 2*(hi-lo+height))
+	}
+	return diameter, width, area, perimeter
+}
+
+func close_to(a, b, scale float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(scale, 1)
+}
+
+// Check that r encloses polygon, and that its reported measures and supports agree with its
+// corners
+func check_rectangle[T Coord](t *testing.T, polygon [][2]T, r Rectangle, scale float64) {
+	t.Helper()
+	side := func(i int) float64 {
+		return math.Hypot(r.Corners[(i+1)%4][0]-r.Corners[i][0], r.Corners[(i+1)%4][1]-r.Corners[i][1])
+	}
+	if !close_to(side(0)*side(1), r.Area, scale*scale) || !close_to(2*(side(0)+side(1)), r.Perimeter, scale) {
+		t.Fatalf("rectangle %v: area %g and perimeter %g don't match its sides", r.Corners, r.Area, r.Perimeter)
+	}
+	// Every point is on or to the left of every side, and each side touches its support
+	for i := 0; i < 4; i++ {
+		a, b := r.Corners[i], r.Corners[(i+1)%4]
+		length := side(i)
+		if length == 0 {
+			continue
+		}
+		height := func(p [2]T) float64 {
+			return ((b[0]-a[0])*(float64(p[1])-a[1]) - (b[1]-a[1])*(float64(p[0])-a[0])) / length
+		}
+		for _, p := range polygon {
+			if height(p) < -1e-9*math.Max(scale, 1) {
+				t.Fatalf("rectangle %v: %v is outside side %d", r.Corners, p, i)
+			}
+		}
+		if !close_to(height(polygon[r.Support[i]]), 0, scale) {
+			t.Fatalf("rectangle %v: support %v is off side %d", r.Corners, polygon[r.Support[i]], i)
+		}
+	}
+}
+
+// Check the calipers of a convex polygon against brute force
+func check_calipers[T Coord](t *testing.T, polygon [][2]T) {
+	t.Helper()
+	c, err := RotatingCalipers(polygon)
+	if err != nil {
+		t.Fatalf("calipers of %v: %v", polygon, err)
+	}
+	diameter, width, area, perimeter := brute_force_calipers(polygon)
+	scale := diameter
+	if !close_to(c.Diameter, diameter, scale) || c.Diameter != dist(polygon[c.DiameterPair[0]], polygon[c.DiameterPair[1]]) {
+		t.Fatalf("calipers of %v: diameter %g between %v, want %g", polygon, c.Diameter, c.DiameterPair, diameter)
+	}
+	if math.IsInf(width, 1) {
+		width, area, perimeter = 0, 0, 2*diameter
+	}
+	if !close_to(c.Width, width, scale) {
+		t.Fatalf("calipers of %v: width %g, want %g", polygon, c.Width, width)
+	}
+	if !close_to(c.MinArea.Area, area, scale*scale) || !close_to(c.MinPerimeter.Perimeter, perimeter, scale) {
+		t.Fatalf("calipers of %v: area %g and perimeter %g, want %g and %g", polygon, c.MinArea.Area, c.MinPerimeter.Perimeter, area, perimeter)
+	}
+	check_rectangle(t, polygon, c.MinArea, scale)
+	check_rectangle(t, polygon, c.MinPerimeter, scale)
+}
+
+func TestCalipersBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		points := random_points[int64](r, 3+r.Intn(30))
+		if trial%2 == 0 {
+			for i := range points {
+				points[i][0] %= 1000
+				points[i][1] %= 1000
+			}
+		}
+		polygon, err := SeqMonotoneChain(context.Background(), points, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_calipers(t, polygon)
+	}
+	for trial := 0; trial < 200; trial++ {
+		points := make([][2]float64, 3+r.Intn(30))
+		for i := range points {
+			points[i] = [2]float64{r.NormFloat64(), r.NormFloat64()}
+		}
+		polygon, err := SeqMonotoneChain(context.Background(), points, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_calipers(t, polygon)
+	}
+}
+
+func TestCalipersCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygon  [][2]int64
+		diameter float64
+		width    float64
+		area     float64
+	}{
+		{"square", [][2]int64{{0, 0}, {2, 0}, {2, 2}, {0, 2}}, math.Sqrt(8), 2, 4},
+		{"clockwise rectangle", [][2]int64{{0, 0}, {0, 1}, {3, 1}, {3, 0}}, math.Sqrt(10), 1, 3},
+		{"triangle", [][2]int64{{0, 0}, {4, 0}, {0, 3}}, 5, 12.0 / 5, 12},
+		{"collinear boundary points", [][2]int64{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {1, 2}, {0, 2}, {0, 1}}, math.Sqrt(8), 2, 4},
+		{"repeated vertices", [][2]int64{{0, 0}, {0, 0}, {2, 0}, {2, 2}, {2, 2}, {0, 2}, {0, 0}}, math.Sqrt(8), 2, 4},
+		{"diamond", [][2]int64{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}, 2, math.Sqrt(2), 2},
+	}
+	for _, test := range tests {
+		c, err := RotatingCalipers(test.polygon)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !close_to(c.Diameter, test.diameter, 1) || !close_to(c.Width, test.width, 1) || !close_to(c.MinArea.Area, test.area, 1) {
+			t.Fatalf("%s: got diameter %g, width %g, area %g, want %g, %g, %g", test.name, c.Diameter, c.Width, c.MinArea.Area, test.diameter, test.width, test.area)
+		}
+		check_calipers(t, test.polygon)
+	}
+}
+
+func TestCalipersDegenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygon  [][2]int64
+		diameter float64
+		pair     [2]int
+	}{
+		{"one point", [][2]int64{{3, 4}}, 0, [2]int{0, 0}},
+		{"repeated point", [][2]int64{{3, 4}, {3, 4}, {3, 4}}, 0, [2]int{0, 0}},
+		{"two points", [][2]int64{{0, 0}, {3, 4}}, 5, [2]int{0, 1}},
+		{"collinear", [][2]int64{{1, 1}, {2, 2}, {4, 4}, {3, 3}}, math.Sqrt(18), [2]int{0, 2}},
+		{"collinear backwards", [][2]int64{{2, 2}, {4, 4}, {0, 0}}, math.Sqrt(32), [2]int{2, 1}},
+	}
+	for _, test := range tests {
+		c, err := RotatingCalipers(test.polygon)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !close_to(c.Diameter, test.diameter, 1) || c.DiameterPair != test.pair {
+			t.Fatalf("%s: diameter %g between %v, want %g between %v", test.name, c.Diameter, c.DiameterPair, test.diameter, test.pair)
+		}
+		if c.Width != 0 || c.MinArea.Area != 0 || !close_to(c.MinPerimeter.Perimeter, 2*test.diameter, 1) {
+			t.Fatalf("%s: width %g, area %g, perimeter %g, want 0, 0, %g", test.name, c.Width, c.MinArea.Area, c.MinPerimeter.Perimeter, 2*test.diameter)
+		}
+		check_rectangle(t, test.polygon, c.MinArea, 1)
+	}
+
+	if _, err := RotatingCalipers([][2]int64{}); !errors.Is(err, ErrEmptyPolygon) {
+		t.Fatalf("empty polygon: got %v, want %v", err, ErrEmptyPolygon)
+	}
+	if _, err := RotatingCalipers([][2]int64{{0, 0}, {2, 0}, {1, 1}, {2, 2}, {0, 2}}); !errors.Is(err, ErrNotConvex) {
+		t.Fatalf("reflex vertex: got %v, want %v", err, ErrNotConvex)
+	}
+}
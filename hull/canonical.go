@@ -0,0 +1,63 @@
+package hull
+
+import "sort"
+
+// Index of the lowest point, leftmost of ties. This is where canonical hulls start.
+func lowest_leftmost[T Coord](points [][2]T) int {
+	index := -1
+	for i, point := range points {
+		if index == -1 || point[1] < points[index][1] || (point[1] == points[index][1] && point[0] < points[index][0]) {
+			index = i
+		}
+	}
+	return index
+}
+
+// Put hull vertices (in any order, duplicates allowed) into canonical form: a counterclockwise
+// polygon starting from the lowest-then-leftmost vertex, or clockwise from the same vertex.
+// Output is independent of the algorithm so hulls can be compared directly.
+func canonical_hull[T Coord](hull [][2]T, clockwise bool) [][2]T {
+	if len(hull) == 0 {
+		return hull
+	}
+	pivot := hull[lowest_leftmost(hull)]
+	res := make([][2]T, 1, len(hull))
+	res[0] = pivot
+	// Copies of pivot would compare equal to everything, leave them out of the sort
+	for _, point := range hull {
+		if point != pivot {
+			res = append(res, point)
+		}
+	}
+
+	// Every other vertex is above (or right of) pivot, so sorting by angle gives the polygon order.
+	// Vertices are in convex position so only duplicates compare equal.
+	rest := res[1:]
+	sort.Slice(rest, func(i, j int) bool {
+		return orient(pivot, rest[i], rest[j]) > 0
+	})
+
+	// Drop duplicates, which are adjacent after sorting
+	res = dedupe_sorted(res)
+
+	if clockwise {
+		reverse(res[1:])
+	}
+	return res
+}
+
+// Canonical order for a hull that includes collinear boundary points, which are already
+// counterclockwise from the lowest-then-leftmost vertex
+func canonical_hull_inclusive[T Coord](hull [][2]T, clockwise bool) [][2]T {
+	if clockwise && len(hull) > 1 {
+		reverse(hull[1:])
+	}
+	return hull
+}
+
+// Reverse points in place
+func reverse[T Coord](points [][2]T) {
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+}
@@ -0,0 +1,18 @@
+package hull
+
+import "testing"
+
+func TestCanonicalHullInclusiveSmall(t *testing.T) {
+	for _, clockwise := range []bool{false, true} {
+		if hull := canonical_hull_inclusive([][2]int64{}, clockwise); len(hull) != 0 {
+			t.Fatalf("clockwise=%v: got %v, want an empty hull", clockwise, hull)
+		}
+		if hull := canonical_hull_inclusive([][2]int64{{1, 2}}, clockwise); len(hull) != 1 || hull[0] != [2]int64{1, 2} {
+			t.Fatalf("clockwise=%v: got %v, want [[1 2]]", clockwise, hull)
+		}
+	}
+	hull := canonical_hull_inclusive([][2]int64{{0, 0}, {2, 0}, {1, 1}}, true)
+	if !equal_hulls(hull, [][2]int64{{0, 0}, {1, 1}, {2, 0}}) {
+		t.Fatalf("got %v, want [[0 0] [1 1] [2 0]]", hull)
+	}
+}
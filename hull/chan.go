@@ -1,40 +1,36 @@
-package main
+package hull
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"time"
 )
 
-var tangent_time time.Duration
-var leftmost_time time.Duration
-
 // Find point on this convex subhull that is as left as possible from point p (p must not be inside the subhull)
-func find_tangent(subhull [][2]float32, p [2]float32, order float32) [2]float32 {
-	start := time.Now()
+func find_tangent[T Coord](subhull [][2]T, p [2]T, order int) [2]T {
 	endpoint := 0
 	// Look through this subhull
 	for i := 1; i < len(subhull); i++ {
-		cross := cross_prod(p, subhull[endpoint], subhull[i]) * order
-		if (subhull[endpoint][0] == p[0] && subhull[endpoint][1] == p[1]) || cross > 0 {
+		turn := orient(p, subhull[endpoint], subhull[i]) * order
+		if subhull[endpoint] == p || turn > 0 {
 			// New point is to the left of current endpoint
 			endpoint = i
-		} else if cross == 0 && dist(p, subhull[i]) >= dist(p, subhull[endpoint]) {
+		} else if turn == 0 && farther(p, subhull[i], subhull[endpoint]) {
 			// New point is collinear but further than current endpoint
 			endpoint = i
 		}
 	}
-	tangent_time += time.Since(start)
 	return subhull[endpoint]
 }
 
 // Left of line a->b
-func above(a, b, c [2]float32) bool {
-	return cross_prod(a, b, c) > 0
+func above[T Coord](a, b, c [2]T) bool {
+	return orient(a, b, c) > 0
 }
 
 // Right of line a->b
-func below(a, b, c [2]float32) bool {
-	return cross_prod(a, b, c) < 0
+func below[T Coord](a, b, c [2]T) bool {
+	return orient(a, b, c) < 0
 }
 
 // Binary search from point to leftmost tangent on a convex hull (not described in paper)
@@ -42,7 +38,7 @@ func below(a, b, c [2]float32) bool {
 // Key intuition - consider points on convex hull as directed edges from V[0] -> V[1]
 // 		Use the direction of these vectors relative to P as the "order" so you can bsearch,
 //		leads to some casework
-func find_tangent_bsearch(V [][2]float32, P [2]float32, order float32) [2]float32 {
+func find_tangent_bsearch[T Coord](V [][2]T, P [2]T, order int) [2]T {
 	n := len(V)
 	if n < 3 {
 		// Do not need to binary search if less than 3 points
@@ -68,7 +64,7 @@ func find_tangent_bsearch(V [][2]float32, P [2]float32, order float32) [2]float3
 
 	dnC := false
 	dnA := false
-	if above(P, V[n-1], V[0]) && !below(P, V[1], V[0]) {
+	if above(P, V[n-1], V[0]) && !below(P, V[1], V[0]) && is_tangent(V, P, 0, order) {
 		return V[0]
 	}
 
@@ -87,9 +83,12 @@ func find_tangent_bsearch(V [][2]float32, P [2]float32, order float32) [2]float3
 
 		c = (a + b) / 2
 		dnC = below(P, V[mod(c+1, n)], V[c])
-		if above(P, V[c-1], V[c]) && !dnC {
+		if above(P, V[mod(c-1+n, n)], V[c]) && !dnC {
 			// Found maximum tangent point
-			return V[c]
+			if is_tangent(V, P, c, order) {
+				return V[c]
+			}
+			break
 		}
 
 		// Update binary search range based on casework from orientation of edge segments
@@ -124,21 +123,37 @@ func find_tangent_bsearch(V [][2]float32, P [2]float32, order float32) [2]float3
 			return V[mod(i+1, n)]
 		}
 	}
-	fmt.Println("failed to find!")
-	return [2]float32{-1, -1}
+	// Search did not converge on a tangent (nearly degenerate subhull), fall back to a linear scan
+	return find_tangent(V, P, order)
 }
 
-// Jarvis march on subhulls
-func subhull_jarvis(points [][2]float32, subhull_sizes []int, group_size int) [][2]float32 {
-	var hull [][2]float32
+// Whether V[i] is the tangent point from P, i.e. no vertex is left of P->V[i] (right for order -1)
+// and no collinear vertex is further. Checking the neighbors is enough since V is convex and P is outside.
+func is_tangent[T Coord](V [][2]T, P [2]T, i int, order int) bool {
+	n := len(V)
+	if V[i] == P {
+		return false
+	}
+	for _, j := range [2]int{mod(i-1+n, n), mod(i+1, n)} {
+		turn := orient(P, V[i], V[j]) * order
+		if turn > 0 || (turn == 0 && farther(P, V[j], V[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Jarvis march on subhulls, nil if group_size is too small (or ctx is cancelled)
+func subhull_jarvis[T Coord](ctx context.Context, points [][2]T, subhull_sizes []int, group_size int) [][2]T {
+	var hull [][2]T
 	n_subhulls := len(subhull_sizes)
-	candidates := make([][2]float32, n_subhulls)
+	candidates := make([][2]T, n_subhulls)
 
 	// Leftmost point starts as first point on hull
 	left := leftmost(points)
 	cur_p := points[left]
 	// If need to add more points than the group size, retry with different group size
-	for step := 0; step < group_size; step++ {
+	for step := 0; step < group_size && !done(ctx); step++ {
 		hull = append(hull, cur_p)
 		subhull_index := 0
 		// Compute the tangent point for each of the subhulls
@@ -146,30 +161,26 @@ func subhull_jarvis(points [][2]float32, subhull_sizes []int, group_size int) []
 			start := subhull_index
 			end := subhull_index + subhull_sizes[i]
 			// NOTE: using bsearch may lead to slightly different results due to colinearity (especially on uniform)
-			candidates[i] = find_tangent_bsearch(points[start:end], cur_p, 1.0)
+			candidates[i] = find_tangent_bsearch(points[start:end], cur_p, 1)
 			subhull_index += subhull_sizes[i]
 		}
 
 		// Find leftmost endpoint out of all of the subhulls
 		endpoint := 0
-		leftmost_search_start := time.Now()
 		for candidate := range candidates {
-			cross := cross_prod(cur_p, candidates[endpoint], candidates[candidate])
-			if (candidates[endpoint][0] == cur_p[0] && candidates[endpoint][1] == cur_p[1]) || cross > 0 {
+			turn := orient(cur_p, candidates[endpoint], candidates[candidate])
+			if candidates[endpoint] == cur_p || turn > 0 {
 				// New point is to the left of current endpoint
 				endpoint = candidate
-			} else if cross == 0 && dist(cur_p, candidates[candidate]) >= dist(cur_p, candidates[endpoint]) {
-				if dist(cur_p, points[candidate]) == dist(cur_p, points[endpoint]) && endpoint != left {
-					// New point is collinear but further than current endpoint
-					endpoint = candidate
-				}
+			} else if turn == 0 && farther(cur_p, candidates[candidate], candidates[endpoint]) {
+				// New point is collinear but further than current endpoint
+				endpoint = candidate
 			}
 		}
-		leftmost_time += time.Since(leftmost_search_start)
 		cur_p = candidates[endpoint]
 
 		// Circled back to original point
-		if cur_p[0] == points[left][0] && cur_p[1] == points[left][1] {
+		if cur_p == points[left] {
 			return hull
 		}
 		if len(hull) == len(points) {
@@ -181,13 +192,13 @@ func subhull_jarvis(points [][2]float32, subhull_sizes []int, group_size int) []
 }
 
 // Sequential Chan's algorithm O(nlogh)
-func seq_chans(points [][2]float32) [][2]float32 {
+func seq_chans[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
 	n := len(points)
 
 	var t uint
 	t = 3 // Init group size as 2^2^3 = 256
 	start := time.Now()
-	subhulls := make([][2]float32, 0, n>>1) // n>>1 Just a guess on how many points will be in subhulls
+	subhulls := make([][2]T, 0, n>>1) // n>>1 Just a guess on how many points will be in subhulls
 	subhull_sizes := make([]int, 0, n/(1<<(1<<t))+1)
 	debug("initial allocation time", time.Since(start))
 
@@ -198,8 +209,7 @@ func seq_chans(points [][2]float32) [][2]float32 {
 		// Try out new group size (estimate of # points on convex hull)
 		group_size := 1 << (1 << t)
 		if group_size == 0 {
-			fmt.Println("chan's failed, too many iterations")
-			return nil
+			return nil, errors.New("hull: chan's failed, too many iterations")
 		}
 		debug("current group size", group_size)
 		if n < group_size {
@@ -221,7 +231,10 @@ func seq_chans(points [][2]float32) [][2]float32 {
 			// Compute convex hull of subgroup
 			subhull_start := time.Now()
 			// Use graham scan
-			subhull := seq_graham_scan(points[start:end])
+			subhull, err := seq_graham_scan(ctx, points[start:end])
+			if err != nil {
+				return nil, err
+			}
 			subhull_compute += time.Since(subhull_start)
 
 			// Add subhull points
@@ -239,12 +252,15 @@ func seq_chans(points [][2]float32) [][2]float32 {
 		 * Jarvis March (gift wrap) of subhulls *
 		 ****************************************/
 		march_start := time.Now()
-		var hull [][2]float32
-		hull = subhull_jarvis(subhulls, subhull_sizes, group_size)
+		var hull [][2]T
+		hull = subhull_jarvis(ctx, subhulls, subhull_sizes, group_size)
 		debug("march time", time.Since(march_start))
 
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if hull != nil {
-			return hull
+			return hull, nil
 		}
 		// Group size too small
 		t++
@@ -0,0 +1,493 @@
+package hull
+
+import (
+	"context"
+	"errors"
+)
+
+/**********************
+ * Combining hulls    *
+ **********************
+Operations on two convex polygons in linear time, taking the ordered hulls the algorithms return.
+
+Intersection is O'Rourke's edge chase (Computational Geometry in C, 7.6). Two edges, one of each
+polygon, advance around their boundaries so that whichever is aiming at the other's line moves,
+and where they cross the polygon that is inside switches. Each edge is passed at most twice, so it
+is O(n + m). Every decision uses exact predicates, only the crossing points are rounded to float64.
+When the boundaries never cross, one polygon contains the other, or they only touch, or they are
+disjoint.
+
+The hull of the union merges the vertices of both polygons, each already a lexicographically
+increasing lower chain and decreasing upper chain, and runs one pass of monotone chain on them.
+
+The Minkowski sum merges the edges of both polygons by angle starting from their lowest vertices,
+whose sum is the lowest vertex of the result.
+*/
+
+var ErrOverflow = errors.New("hull: coordinates overflow")
+
+// Which polygon's boundary is inside the other's along the current edges
+const (
+	in_unknown = iota
+	in_p
+	in_q
+)
+
+// How two segments meet
+const (
+	seg_none = iota
+	// At a point inside both
+	seg_proper
+	// At an endpoint of one of them
+	seg_vertex
+	// Collinear, meeting in a segment (possibly a single point)
+	seg_overlap
+)
+
+// Intersection returns the intersection of two convex polygons, each given in order clockwise or
+// counterclockwise (see NewLocator), as a canonical counterclockwise polygon. Crossing points are
+// rounded to float64. Polygons that only touch give 1 or 2 points, disjoint ones an empty result.
+// O(n + m), or O(n log m + m log n) when the boundaries do not cross.
+func Intersection[T Coord](a, b [][2]T) ([][2]float64, error) {
+	p, err := convex_polygon(a)
+	if err != nil {
+		return nil, err
+	}
+	q, err := convex_polygon(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var res [][2]float64
+	switch {
+	case len(p) < 3 && len(q) < 3:
+		res = degenerate_intersection(p, q)
+	case len(p) < 3:
+		res = clip_segment(p, q)
+	case len(q) < 3:
+		res = clip_segment(q, p)
+	default:
+		res = chase_intersection(p, q)
+	}
+	return rotate_lowest(res), nil
+}
+
+// O'Rourke's intersection of counterclockwise, strictly convex polygons with at least 3 vertices
+func chase_intersection[T Coord](p, q [][2]T) [][2]float64 {
+	n, m := len(p), len(q)
+	var res [][2]float64
+	emit := func(v [2]float64) {
+		if len(res) == 0 || res[len(res)-1] != v {
+			res = append(res, v)
+		}
+	}
+
+	// Edges p[a-1]->p[a] and q[b-1]->q[b], with how many times each has advanced
+	a, b, a_steps, b_steps := 0, 0, 0, 0
+	inside := in_unknown
+	crossed := false
+	advance_p := func() {
+		if inside == in_p {
+			emit(to_float64(p[a]))
+		}
+		a, a_steps = mod(a+1, n), a_steps+1
+	}
+	advance_q := func() {
+		if inside == in_q {
+			emit(to_float64(q[b]))
+		}
+		b, b_steps = mod(b+1, m), b_steps+1
+	}
+
+	for (a_steps < n || b_steps < m) && a_steps < 2*n && b_steps < 2*m {
+		a1, b1 := mod(a+n-1, n), mod(b+m-1, m)
+		turn := cross_sign(p[a1], p[a], q[b1], q[b])
+		// Whether the head of each edge is inside the other edge's half-plane
+		a_side := orient(q[b1], q[b], p[a])
+		b_side := orient(p[a1], p[a], q[b])
+
+		code, x, y := seg_intersect(p[a1], p[a], q[b1], q[b])
+		if code == seg_proper || code == seg_vertex {
+			if !crossed {
+				// Go around once more from the first crossing
+				a_steps, b_steps = 0, 0
+				crossed = true
+			}
+			emit(x)
+			if a_side > 0 {
+				inside = in_p
+			} else if b_side > 0 {
+				inside = in_q
+			}
+		}
+
+		switch {
+		case code == seg_overlap && dot_sign(p[a1], p[a], q[b1], q[b]) < 0:
+			// Opposite edges overlapping, the polygons are on either side and meet in a segment
+			if x == y {
+				return [][2]float64{x}
+			}
+			return [][2]float64{x, y}
+		case turn == 0 && a_side < 0 && b_side < 0:
+			// Parallel and each outside the other
+			return nil
+		case turn == 0 && a_side == 0 && b_side == 0:
+			// Collinear, advance whichever is outside without emitting
+			if inside == in_p {
+				advance_q()
+			} else {
+				advance_p()
+			}
+		case turn >= 0:
+			if b_side > 0 {
+				advance_p()
+			} else {
+				advance_q()
+			}
+		default:
+			if a_side > 0 {
+				advance_q()
+			} else {
+				advance_p()
+			}
+		}
+	}
+
+	if inside == in_unknown {
+		return uncrossed_intersection(p, q)
+	}
+	if len(res) > 1 && res[0] == res[len(res)-1] {
+		res = res[:len(res)-1]
+	}
+	return res
+}
+
+// Intersection of polygons whose boundaries do not cross: one of them if it is inside the other,
+// otherwise the points or segment where they touch, if any
+func uncrossed_intersection[T Coord](p, q [][2]T) [][2]float64 {
+	in_p := &Locator[T]{hull: p}
+	in_q := &Locator[T]{hull: q}
+	within := func(inner [][2]T, outer *Locator[T]) bool {
+		for _, v := range inner {
+			if outer.Locate(v) == Outside {
+				return false
+			}
+		}
+		return true
+	}
+	if within(p, in_q) {
+		return to_float64s(p)
+	}
+	if within(q, in_p) {
+		return to_float64s(q)
+	}
+
+	// Touching with disjoint interiors, in a point or a segment whose ends are vertices
+	var touching [][2]T
+	for _, v := range p {
+		if in_q.Locate(v) == Boundary {
+			touching = append(touching, v)
+		}
+	}
+	for _, v := range q {
+		if in_p.Locate(v) == Boundary {
+			touching = append(touching, v)
+		}
+	}
+	if len(touching) == 0 {
+		return nil
+	}
+	var ends [][2]T
+	for _, i := range line_extremes(touching, all_indices(len(touching))) {
+		ends = append(ends, touching[i])
+	}
+	return to_float64s(ends)
+}
+
+// Intersection of a point or segment s with a counterclockwise, strictly convex polygon with at
+// least 3 vertices. The segment is cut by the line of each edge in turn.
+func clip_segment[T Coord](s, polygon [][2]T) [][2]float64 {
+	if len(s) == 1 {
+		if (&Locator[T]{hull: polygon}).Locate(s[0]) == Outside {
+			return nil
+		}
+		return to_float64s(s)
+	}
+
+	// The part of s kept is from parameter lo to hi, ends stay exact until they are cut
+	start, end := to_float64(s[0]), to_float64(s[1])
+	lo, hi := 0.0, 1.0
+	lo_point, hi_point := start, end
+	at := func(t float64) [2]float64 {
+		return [2]float64{start[0] + t*(end[0]-start[0]), start[1] + t*(end[1]-start[1])}
+	}
+	for i := range polygon {
+		a, b := polygon[i], polygon[mod(i+1, len(polygon))]
+		s0, s1 := orient(a, b, s[0]), orient(a, b, s[1])
+		switch {
+		case s0 >= 0 && s1 >= 0:
+		case s0 < 0 && s1 < 0:
+			return nil
+		case s0 < 0 && s1 == 0:
+			lo, lo_point = 1, end
+		case s0 == 0 && s1 < 0:
+			hi, hi_point = 0, start
+		case s0 < 0:
+			if t := line_crossing(a, b, s[0], s[1]); t > lo {
+				lo, lo_point = t, at(t)
+			}
+		default:
+			if t := line_crossing(a, b, s[0], s[1]); t < hi {
+				hi, hi_point = t, at(t)
+			}
+		}
+	}
+	switch {
+	case lo > hi:
+		return nil
+	case lo_point == hi_point:
+		return [][2]float64{lo_point}
+	}
+	return [][2]float64{lo_point, hi_point}
+}
+
+// Intersection of two points or segments
+func degenerate_intersection[T Coord](p, q [][2]T) [][2]float64 {
+	if len(p) > len(q) {
+		p, q = q, p
+	}
+	if len(p) == 1 {
+		on := p[0] == q[0]
+		if len(q) == 2 {
+			on = orient(q[0], q[1], p[0]) == 0 && along(q[0], q[1], q[0], p[0]) >= 0 && along(q[0], q[1], p[0], q[1]) >= 0
+		}
+		if !on {
+			return nil
+		}
+		return to_float64s(p)
+	}
+	code, x, y := seg_intersect(p[0], p[1], q[0], q[1])
+	switch {
+	case code == seg_none:
+		return nil
+	case code == seg_overlap && x != y:
+		return [][2]float64{x, y}
+	}
+	return [][2]float64{x}
+}
+
+// How segments ab and cd meet, and where: the crossing or touching point, or the ends of the overlap
+func seg_intersect[T Coord](a, b, c, d [2]T) (int, [2]float64, [2]float64) {
+	var none [2]float64
+	o1, o2 := orient(a, b, c), orient(a, b, d)
+	if o1 == 0 && o2 == 0 {
+		// Collinear, order c and d along ab and overlap the two ranges
+		if along(a, b, c, d) < 0 {
+			c, d = d, c
+		}
+		start, end := a, b
+		if along(a, b, a, c) > 0 {
+			start = c
+		}
+		if along(a, b, d, b) > 0 {
+			end = d
+		}
+		if along(a, b, start, end) < 0 {
+			return seg_none, none, none
+		}
+		return seg_overlap, to_float64(start), to_float64(end)
+	}
+	o3, o4 := orient(c, d, a), orient(c, d, b)
+	if o1*o2 > 0 || o3*o4 > 0 {
+		return seg_none, none, none
+	}
+	switch {
+	case o1 == 0:
+		return seg_vertex, to_float64(c), none
+	case o2 == 0:
+		return seg_vertex, to_float64(d), none
+	case o3 == 0:
+		return seg_vertex, to_float64(a), none
+	case o4 == 0:
+		return seg_vertex, to_float64(b), none
+	}
+	t := line_crossing(c, d, a, b)
+	fa, fb := to_float64(a), to_float64(b)
+	return seg_proper, [2]float64{fa[0] + t*(fb[0]-fa[0]), fa[1] + t*(fb[1]-fa[1])}, none
+}
+
+// Parameter t at which the segment from s to e crosses the line through a and b, which it is
+// known to cross
+func line_crossing[T Coord](a, b, s, e [2]T) float64 {
+	fa, fb, fs, fe := to_float64(a), to_float64(b), to_float64(s), to_float64(e)
+	dx, dy := fb[0]-fa[0], fb[1]-fa[1]
+	from_s := dx*(fs[1]-fa[1]) - dy*(fs[0]-fa[0])
+	from_e := dx*(fe[1]-fa[1]) - dy*(fe[0]-fa[0])
+	return min(max(from_s/(from_s-from_e), 0), 1)
+}
+
+// UnionHull returns the hull of two convex polygons, given in order clockwise or counterclockwise
+// (see NewLocator), in canonical counterclockwise form. It only looks at the polygons' vertices,
+// so the hull of two point sets is the union hull of their hulls. O(n + m)
+func UnionHull[T Coord](a, b [][2]T) ([][2]T, error) {
+	if len(a) == 0 && len(b) == 0 {
+		return nil, ErrEmptyPolygon
+	}
+	var sorted [2][][2]T
+	for i, polygon := range [2][][2]T{a, b} {
+		if len(polygon) == 0 {
+			continue
+		}
+		p, err := convex_polygon(polygon)
+		if err != nil {
+			return nil, err
+		}
+		sorted[i] = lex_sorted(p)
+	}
+
+	// Merge, dropping vertices shared by both
+	merged := make([][2]T, 0, len(sorted[0])+len(sorted[1]))
+	i, j := 0, 0
+	for i < len(sorted[0]) || j < len(sorted[1]) {
+		var next [2]T
+		if j == len(sorted[1]) || (i < len(sorted[0]) && lex_less(sorted[0][i], sorted[1][j])) {
+			next, i = sorted[0][i], i+1
+		} else {
+			next, j = sorted[1][j], j+1
+		}
+		if len(merged) == 0 || merged[len(merged)-1] != next {
+			merged = append(merged, next)
+		}
+	}
+	if len(merged) == 1 {
+		return merged, nil
+	}
+	res := sorted_monotone_chain(context.Background(), merged)
+	return rotate_lowest(res), nil
+}
+
+// Vertices of a counterclockwise strictly convex polygon in lexicographic order. From the
+// lexicographically smallest vertex the boundary goes up to the largest and then back down, so the
+// two runs are merged. O(h)
+func lex_sorted[T Coord](polygon [][2]T) [][2]T {
+	first := 0
+	for i, v := range polygon {
+		if lex_less(v, polygon[first]) {
+			first = i
+		}
+	}
+	polygon = rotate_to(polygon, first)
+	top := 0
+	for top+1 < len(polygon) && lex_less(polygon[top], polygon[top+1]) {
+		top++
+	}
+	up, down := polygon[:top+1], polygon[top+1:]
+
+	res := make([][2]T, 0, len(polygon))
+	i, j := 0, len(down)-1
+	for i < len(up) || j >= 0 {
+		if j < 0 || (i < len(up) && lex_less(up[i], down[j])) {
+			res = append(res, up[i])
+			i++
+		} else {
+			res = append(res, down[j])
+			j--
+		}
+	}
+	return res
+}
+
+// MinkowskiSum returns the Minkowski sum of two convex polygons (every sum of a point of one and
+// a point of the other), each given in order clockwise or counterclockwise (see NewLocator), in
+// canonical counterclockwise form. Integer coordinates return ErrOverflow if a sum does not fit.
+// O(n + m)
+func MinkowskiSum[T Coord](a, b [][2]T) ([][2]T, error) {
+	p, err := convex_polygon(a)
+	if err != nil {
+		return nil, err
+	}
+	q, err := convex_polygon(b)
+	if err != nil {
+		return nil, err
+	}
+	p, q = rotate_to(p, lowest_leftmost(p)), rotate_to(q, lowest_leftmost(q))
+
+	// Edges of p and q merged by angle. Both start at angle 0 or just above and make exactly one
+	// turn, so the sign of their cross product orders them. A point has one edge of length 0,
+	// which is parallel to everything.
+	n, m := len(p), len(q)
+	res := make([][2]T, 0, n+m)
+	i, j := 0, 0
+	for i < n || j < m {
+		v, ok := add_points(p[mod(i, n)], q[mod(j, m)])
+		if !ok {
+			return nil, ErrOverflow
+		}
+		res = append(res, v)
+		turn := cross_sign(p[mod(i, n)], p[mod(i+1, n)], q[mod(j, m)], q[mod(j+1, m)])
+		if turn >= 0 && i < n {
+			i++
+		}
+		if turn <= 0 && j < m {
+			j++
+		}
+	}
+	return res, nil
+}
+
+// a + b, and false if an integer sum overflows
+func add_points[T Coord](a, b [2]T) ([2]T, bool) {
+	var res [2]T
+	for k := range res {
+		res[k] = a[k] + b[k]
+		if !is_float[T]() && (a[k] > 0 && b[k] > 0 && res[k] < 0 || a[k] < 0 && b[k] < 0 && res[k] >= 0) {
+			return res, false
+		}
+	}
+	return res, true
+}
+
+// Strictly convex vertices of polygon counterclockwise, or its 1 or 2 extreme points if it has no area
+func convex_polygon[T Coord](polygon [][2]T) ([][2]T, error) {
+	if len(polygon) == 0 {
+		return nil, ErrEmptyPolygon
+	}
+	vertices, err := convex_vertices(polygon)
+	if err != nil {
+		return nil, err
+	}
+	res := make([][2]T, len(vertices))
+	for i, v := range vertices {
+		res[i] = polygon[v]
+	}
+	return res, nil
+}
+
+// Polygon starting from vertex i
+func rotate_to[P any](polygon []P, i int) []P {
+	return append(polygon[i:len(polygon):len(polygon)], polygon[:i]...)
+}
+
+// Polygon starting from its lowest, then leftmost vertex, as canonical hulls do
+func rotate_lowest[T Coord](polygon [][2]T) [][2]T {
+	if len(polygon) == 0 {
+		return polygon
+	}
+	return rotate_to(polygon, lowest_leftmost(polygon))
+}
+
+func to_float64s[T Coord](points [][2]T) [][2]float64 {
+	res := make([][2]float64, len(points))
+	for i, p := range points {
+		res[i] = to_float64(p)
+	}
+	return res
+}
+
+func all_indices(n int) []int {
+	res := make([]int, n)
+	for i := range res {
+		res[i] = i
+	}
+	return res
+}
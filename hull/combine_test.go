@@ -0,0 +1,162 @@
+package hull
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Strict counterclockwise hull of points, in canonical form
+func reference_hull[T Coord](t *testing.T, points [][2]T) [][2]T {
+	t.Helper()
+	h, err := SeqMonotoneChain(context.Background(), append([][2]T{}, points...), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// Intersection of two strictly convex counterclockwise polygons by brute force: the hull of every
+// vertex of one in the other, and of every point where two edges cross
+func brute_force_intersection(t *testing.T, p, q [][2]int64) [][2]float64 {
+	t.Helper()
+	var points [][2]float64
+	for _, pair := range [2][2][][2]int64{{p, q}, {q, p}} {
+		for _, v := range pair[0] {
+			if brute_force_locate(pair[1], v) != Outside {
+				points = append(points, to_float64(v))
+			}
+		}
+	}
+	for i, a := range p {
+		b := p[(i+1)%len(p)]
+		for j, c := range q {
+			d := q[(j+1)%len(q)]
+			// Crossing the interior of both, endpoints on the other segment are found above
+			if orient(a, b, c)*orient(a, b, d) < 0 && orient(c, d, a)*orient(c, d, b) < 0 {
+				s := line_crossing(a, b, c, d)
+				points = append(points, [2]float64{float64(c[0]) + s*float64(d[0]-c[0]), float64(c[1]) + s*float64(d[1]-c[1])})
+			}
+		}
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	return reference_hull(t, points)
+}
+
+// Whether every point of a is within eps of a point of b and the other way around
+func close_point_sets(a, b [][2]float64, eps float64) bool {
+	near := func(a, b [][2]float64) bool {
+		for _, p := range a {
+			if !slices.ContainsFunc(b, func(q [2]float64) bool { return math.Hypot(p[0]-q[0], p[1]-q[1]) <= eps }) {
+				return false
+			}
+		}
+		return true
+	}
+	return near(a, b) && near(b, a)
+}
+
+// Check every operation on a and b, given in any of the ways a hull may come, against brute force
+func check_combine(t *testing.T, a, b [][2]int64) {
+	t.Helper()
+	p, q := reference_hull(t, a), reference_hull(t, b)
+
+	inter, err := Intersection(a, b)
+	if err != nil {
+		t.Fatalf("intersection of %v and %v: %v", a, b, err)
+	}
+	if want := brute_force_intersection(t, p, q); !close_point_sets(inter, want, 1e-9) || len(inter) > len(p)+len(q) {
+		t.Fatalf("intersection of %v and %v:\ngot  %v\nwant %v", a, b, inter, want)
+	}
+	if len(inter) > 0 && lowest_leftmost(inter) != 0 {
+		t.Fatalf("intersection of %v and %v: %v does not start from its lowest vertex", a, b, inter)
+	}
+	for i := 0; len(inter) >= 3 && i < len(inter); i++ {
+		if orient(inter[i], inter[(i+1)%len(inter)], inter[(i+2)%len(inter)]) < 0 {
+			t.Fatalf("intersection of %v and %v: %v is not counterclockwise", a, b, inter)
+		}
+	}
+
+	union, err := UnionHull(a, b)
+	if err != nil {
+		t.Fatalf("union of %v and %v: %v", a, b, err)
+	}
+	if want := reference_hull(t, append(append([][2]int64{}, p...), q...)); !slices.Equal(union, want) {
+		t.Fatalf("union of %v and %v:\ngot  %v\nwant %v", a, b, union, want)
+	}
+
+	sum, err := MinkowskiSum(a, b)
+	if err != nil {
+		t.Fatalf("Minkowski sum of %v and %v: %v", a, b, err)
+	}
+	var sums [][2]int64
+	for _, u := range p {
+		for _, v := range q {
+			sums = append(sums, [2]int64{u[0] + v[0], u[1] + v[1]})
+		}
+	}
+	if want := reference_hull(t, sums); !slices.Equal(sum, want) {
+		t.Fatalf("Minkowski sum of %v and %v:\ngot  %v\nwant %v", a, b, sum, want)
+	}
+}
+
+func TestCombineBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 500; trial++ {
+		// Small grids, so the polygons often share vertices and edges or just touch
+		size := 2 + r.Intn(8)
+		_, a := hull_variants(t, grid_points(r, 1+r.Intn(12), size))
+		_, b := hull_variants(t, grid_points(r, 1+r.Intn(12), size))
+		check_combine(t, a[r.Intn(len(a))], b[r.Intn(len(b))])
+	}
+	for trial := 0; trial < 100; trial++ {
+		a := reference_hull(t, random_points[int64](r, 3+r.Intn(30)))
+		b := reference_hull(t, random_points[int64](r, 3+r.Intn(30)))
+		check_combine(t, a, b)
+	}
+}
+
+func TestCombineCases(t *testing.T) {
+	square := [][2]int64{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	tests := []struct {
+		name         string
+		a, b         [][2]int64
+		intersection [][2]float64
+		union        [][2]int64
+	}{
+		{"disjoint", square, [][2]int64{{5, 5}, {6, 5}, {5, 6}}, nil, [][2]int64{{0, 0}, {2, 0}, {6, 5}, {5, 6}, {0, 2}}},
+		{"nested", square, [][2]int64{{1, 1}, {1, 0}, {2, 1}}, [][2]float64{{1, 0}, {2, 1}, {1, 1}}, square},
+		{"same", square, square, [][2]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}}, square},
+		{"shared edge", square, [][2]int64{{2, 0}, {4, 0}, {4, 2}, {2, 2}}, [][2]float64{{2, 0}, {2, 2}}, [][2]int64{{0, 0}, {4, 0}, {4, 2}, {0, 2}}},
+		{"part of an edge", square, [][2]int64{{2, 1}, {3, 1}, {3, 3}, {2, 3}}, [][2]float64{{2, 1}, {2, 2}}, [][2]int64{{0, 0}, {2, 0}, {3, 1}, {3, 3}, {2, 3}, {0, 2}}},
+		{"touching corners", square, [][2]int64{{2, 2}, {4, 2}, {4, 4}, {2, 4}}, [][2]float64{{2, 2}}, [][2]int64{{0, 0}, {2, 0}, {4, 2}, {4, 4}, {2, 4}, {0, 2}}},
+		{"corner on an edge", square, [][2]int64{{3, 0}, {3, 2}, {2, 1}}, [][2]float64{{2, 1}}, [][2]int64{{0, 0}, {3, 0}, {3, 2}, {0, 2}}},
+		{"crossing", square, [][2]int64{{1, 1}, {3, 1}, {3, 3}, {1, 3}}, [][2]float64{{1, 1}, {2, 1}, {2, 2}, {1, 2}}, [][2]int64{{0, 0}, {2, 0}, {3, 1}, {3, 3}, {1, 3}, {0, 2}}},
+		{"corners on edges", square, [][2]int64{{1, -1}, {3, 1}, {1, 3}, {-1, 1}}, [][2]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}}, [][2]int64{{1, -1}, {3, 1}, {1, 3}, {-1, 1}}},
+		{"point inside", square, [][2]int64{{1, 1}}, [][2]float64{{1, 1}}, square},
+		{"point outside", square, [][2]int64{{3, 3}}, nil, [][2]int64{{0, 0}, {2, 0}, {3, 3}, {0, 2}}},
+		{"segment through", square, [][2]int64{{-1, 1}, {3, 1}}, [][2]float64{{0, 1}, {2, 1}}, [][2]int64{{0, 0}, {2, 0}, {3, 1}, {2, 2}, {0, 2}, {-1, 1}}},
+	}
+	for _, test := range tests {
+		inter, err := Intersection(test.a, test.b)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !slices.Equal(inter, test.intersection) && !(len(inter) == 0 && len(test.intersection) == 0) {
+			t.Fatalf("%s: intersection %v, want %v", test.name, inter, test.intersection)
+		}
+		union, err := UnionHull(test.a, test.b)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !slices.Equal(union, test.union) {
+			t.Fatalf("%s: union %v, want %v", test.name, union, test.union)
+		}
+		check_combine(t, test.a, test.b)
+		check_combine(t, test.b, test.a)
+	}
+}
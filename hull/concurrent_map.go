@@ -1,37 +1,43 @@
-package main
+package hull
 
 import (
 	"math"
 	"sync"
+	"sync/atomic"
 )
 
 // Bucket is a single spot in the table
-type Bucket struct {
+type Bucket[K comparable, V any] struct {
 	mutex *sync.RWMutex
-	entry *Entry
+	entry *Entry[K, V]
 }
 
 // Entry is a single node in the bucket
-type Entry struct {
-	key   [2]float32
-	value [][2]float32
-	next  *Entry
+type Entry[K comparable, V any] struct {
+	key   K
+	value []V
+	next  *Entry[K, V]
 	mutex *sync.Mutex
 }
 
 // SafeMap is a custom concurrent map using buckets
-// Keys are pairs of floats, values are slices of pairs of floats
+// Values are slices, Put appends to the slice already stored for a key
 // Each bucket has a mutex to enable parallel access across keys
-type SafeMap struct {
-	con              []Bucket
+type SafeMap[K comparable, V any] struct {
+	con              []Bucket[K, V]
 	size             int
-	insertions       int // Debug
-	wastedInsertions int // Debug -- waited for writer lock but someone else wrote key
+	hash             func(K) uint64
+	insertions       int64 // Debug (atomic)
+	wastedInsertions int64 // Debug (atomic) -- waited for writer lock but someone else wrote key
 }
 
-// Initialize map to size - won't ever resize
-func (sm *SafeMap) init(size int) {
-	sm.con = make([]Bucket, size)
+// Initialize map to size with a hash function for keys - won't ever resize
+func (sm *SafeMap[K, V]) Init(size int, hash func(K) uint64) {
+	// Need at least one bucket to hash into
+	if size < 1 {
+		size = 1
+	}
+	sm.con = make([]Bucket[K, V], size)
 	for i := 0; i < size; i++ {
 		if sm.con[i].mutex != nil {
 			panic("Reinitializing initialized map")
@@ -39,15 +45,31 @@ func (sm *SafeMap) init(size int) {
 		sm.con[i].mutex = &sync.RWMutex{}
 	}
 	sm.size = size
+	sm.hash = hash
+}
+
+// Hash function for a point (kinda sus)
+func hash_point[T Coord](key [2]T) uint64 {
+	x, y := float64(key[0]), float64(key[1])
+	// -0 == 0, so they need the same hash
+	if x == 0 {
+		x = 0
+	}
+	if y == 0 {
+		y = 0
+	}
+	// Coordinates converted from float32 or small integers have zero low bits, fold the high bits down
+	h := math.Float64bits(x) ^ math.Float64bits(y)*0x9E3779B97F4A7C15
+	return h ^ h>>32
 }
 
-// Hash function for a coordinate (kinda sus)
-func hash(key [2]float32) int {
-	return int(math.Abs(float64(key[0])))
+// Hash function for a range of indices
+func hash_range(key [2]int) uint64 {
+	return uint64(key[0])*31 + uint64(key[1])
 }
 
 // Insert a key into the map
-func (sm *SafeMap) put(key [2]float32, value [][2]float32) {
+func (sm *SafeMap[K, V]) Put(key K, value []V) {
 	// Procedure:
 	//	 Find bucket, acquire read lock
 	//	 See if my key is there
@@ -57,7 +79,7 @@ func (sm *SafeMap) put(key [2]float32, value [][2]float32) {
 	// 		Release read lock, acquire write lock for bucket
 	// 		Acquire write lock for bucket
 	// 		Go to end of linked list and add my key
-	bucketNum := hash(key) % sm.size
+	bucketNum := sm.hash(key) % uint64(sm.size)
 	rwLock := sm.con[bucketNum].mutex
 	if rwLock == nil {
 		panic("RWLOCK NULL")
@@ -82,10 +104,10 @@ func (sm *SafeMap) put(key [2]float32, value [][2]float32) {
 		if entry != nil {
 			// Someone did it for us, we are the only one in the whole bucket so just append
 			entry.value = append(entry.value, value...)
-			sm.wastedInsertions++
+			atomic.AddInt64(&sm.wastedInsertions, 1)
 		} else {
 			addEntry(&sm.con[bucketNum], key, value)
-			sm.insertions++
+			atomic.AddInt64(&sm.insertions, 1)
 		}
 		rwLock.Unlock()
 	}
@@ -93,19 +115,19 @@ func (sm *SafeMap) put(key [2]float32, value [][2]float32) {
 }
 
 // Find a entry in a bucket - return nil if does not exist
-func findEntry(bucket *Bucket, key [2]float32) *Entry {
+func findEntry[K comparable, V any](bucket *Bucket[K, V], key K) *Entry[K, V] {
 	entry := bucket.entry
-	for entry != nil && !(entry.key[0] == key[0] && entry.key[1] == key[1]) {
+	for entry != nil && entry.key != key {
 		entry = entry.next
 	}
 	return entry
 }
 
 // Add an entry to the bucket - assumes the lock for the bucket has been acquired
-func addEntry(bucket *Bucket, key [2]float32, value [][2]float32) {
+func addEntry[K comparable, V any](bucket *Bucket[K, V], key K, value []V) {
 	// See if the bucket has anything
 	if bucket.entry == nil {
-		bucket.entry = &Entry{key, value, nil, &sync.Mutex{}}
+		bucket.entry = &Entry[K, V]{key, value, nil, &sync.Mutex{}}
 	} else {
 		// Add to end of linked list!
 		trail := bucket.entry
@@ -114,17 +136,17 @@ func addEntry(bucket *Bucket, key [2]float32, value [][2]float32) {
 			trail = cur
 			cur = cur.next
 		}
-		trail.next = &Entry{key, value, nil, &sync.Mutex{}}
+		trail.next = &Entry[K, V]{key, value, nil, &sync.Mutex{}}
 	}
 }
 
 // Must be careful with ordering guarantees when using get
-func (sm *SafeMap) get(key [2]float32) [][2]float32 {
-	bucketNum := hash(key) % sm.size
+func (sm *SafeMap[K, V]) Get(key K) []V {
+	bucketNum := sm.hash(key) % uint64(sm.size)
 	rwLock := sm.con[bucketNum].mutex
 	rwLock.RLock()
 	entry := findEntry(&sm.con[bucketNum], key)
-	var res [][2]float32
+	var res []V
 
 	if entry != nil {
 		entry.mutex.Lock()
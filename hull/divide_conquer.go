@@ -0,0 +1,159 @@
+package hull
+
+import (
+	"context"
+	"sync"
+)
+
+/**********************
+ * Divide and conquer *
+ **********************
+Sorts the points lexicographically, splits them in half, hulls each half recursively and merges
+the two hulls with their upper and lower bridges. The halves are separated in lexicographic order,
+so each hull lies entirely on one side of the other and the bridges are mutual tangents: start
+from the facing extreme points and alternate tangents from one hull to the other (binary search,
+see find_tangent_bsearch) until neither end moves.
+*/
+
+// Subproblems this small are hulled directly with monotone chain
+const DC_BASE_LIMIT int = 64
+
+// Subproblems smaller than this are not split across goroutines
+const PAR_DC_LIMIT int = 4096
+
+// Bridge between counterclockwise hulls left and right (left before right lexicographically), upper
+// bridge for side 1 and lower for -1. Returns the indices of its ends in left and right.
+func dc_bridge[T Coord](left, right [][2]T, side int) (int, int) {
+	// Start from the facing extreme points
+	a := left[0]
+	for _, p := range left {
+		if lex_less(a, p) {
+			a = p
+		}
+	}
+	b := right[0]
+	for _, p := range right {
+		if lex_less(p, b) {
+			b = p
+		}
+	}
+
+	// Upper bridge has both hulls right of a->b, lower has them left. Each tangent only turns
+	// the line further the same way, so this stops once a is a tangent from its own b.
+	for {
+		b = find_tangent_bsearch(right, a, side)
+		next := find_tangent_bsearch(left, b, -side)
+		if next == a {
+			break
+		}
+		a = next
+	}
+	return index_of(left, a), index_of(right, b)
+}
+
+// Index of p in points, -1 if missing
+func index_of[T Coord](points [][2]T, p [2]T) int {
+	for i, q := range points {
+		if q == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// Merge counterclockwise hulls left and right (left before right lexicographically) into their hull
+func dc_merge[T Coord](left, right [][2]T) [][2]T {
+	left_up, right_up := dc_bridge(left, right, 1)
+	left_lo, right_lo := dc_bridge(left, right, -1)
+
+	// Right hull from the lower bridge round to the upper one, then the left hull back
+	merged := make([][2]T, 0, len(left)+len(right))
+	for i := right_lo; ; i = mod(i+1, len(right)) {
+		merged = append(merged, right[i])
+		if i == right_up {
+			break
+		}
+	}
+	for i := left_up; ; i = mod(i+1, len(left)) {
+		merged = append(merged, left[i])
+		if i == left_lo {
+			break
+		}
+	}
+	return merged
+}
+
+// Hull of distinct points in lexicographic order, counterclockwise. Returns nil if ctx is cancelled.
+func dc_hull[T Coord](ctx context.Context, points [][2]T, parallel bool) [][2]T {
+	if done(ctx) {
+		return nil
+	}
+	if len(points) < 3 {
+		return append([][2]T(nil), points...)
+	}
+	if len(points) <= DC_BASE_LIMIT {
+		return sorted_monotone_chain(ctx, points)
+	}
+
+	mid := len(points) / 2
+	var left, right [][2]T
+	if parallel && len(points) > PAR_DC_LIMIT {
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			left = dc_hull(ctx, points[:mid], parallel)
+		}()
+		right = dc_hull(ctx, points[mid:], parallel)
+		wg.Wait()
+	} else {
+		left = dc_hull(ctx, points[:mid], parallel)
+		right = dc_hull(ctx, points[mid:], parallel)
+	}
+	if left == nil || right == nil {
+		return nil
+	}
+	return dc_merge(left, right)
+}
+
+// Divide and conquer, hull is returned counterclockwise. points is sorted in place.
+func divide_conquer[T Coord](ctx context.Context, points [][2]T, parallel bool) ([][2]T, error) {
+	if len(points) < 3 {
+		return points, nil
+	}
+	if parallel {
+		wg := new(sync.WaitGroup)
+		wg.Add(1)
+		parallel_qsort(ctx, points, lex_less[T], wg)
+		wg.Wait()
+	} else {
+		qsort(ctx, points, lex_less[T])
+	}
+	if done(ctx) {
+		return nil, ctx.Err()
+	}
+
+	// Drop duplicates so the halves never share a point
+	unique := points[:1]
+	for _, p := range points[1:] {
+		if p != unique[len(unique)-1] {
+			unique = append(unique, p)
+		}
+	}
+
+	hull := dc_hull(ctx, unique, parallel)
+	if hull == nil {
+		return nil, ctx.Err()
+	}
+	return hull, nil
+}
+
+// Run divide and conquer serially
+func seq_divide_conquer[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+	return divide_conquer(ctx, points, false)
+}
+
+// Run divide and conquer with the sort, recursive calls and merges in parallel
+func parallel_divide_conquer[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+	return divide_conquer(ctx, points, true)
+}
@@ -0,0 +1,13 @@
+package hull
+
+import "testing"
+
+func TestDivideConquerSortedGrid(t *testing.T) {
+	check_sorted_grid(t, SeqDivideConquer[int64])
+	check_sorted_grid(t, ParallelDivideConquer[int64])
+}
+
+func TestDivideConquerDeadline(t *testing.T) {
+	check_deadline(t, SeqDivideConquer[int64])
+	check_deadline(t, ParallelDivideConquer[int64])
+}
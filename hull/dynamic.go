@@ -0,0 +1,298 @@
+package hull
+
+/**********************
+ * Dynamic hull       *
+ **********************
+Fully dynamic hull after Overmars and van Leeuwen. The distinct points are the leaves of a weight
+balanced tree in lexicographic order, and every internal node stores only the bridges (lower and
+upper) between the hulls of its two children. The chain of a node is then implicit: its left child's
+chain up to the bridge, the bridge, and its right child's chain from the bridge on. Descending from a
+node towards one end of its bridge, the part of a child's chain still on the node's chain is bounded
+by the bridge ends met on the way (lo and hi below), which is all a search needs to know.
+
+A bridge is found by binary search on the left chain, deciding each step with a tangent search on
+the right chain, so it is O(log^2 n). An update recomputes the bridges on one path (and rebuilds
+subtrees that got out of balance, amortized), O(log^3 n) in total.
+*/
+
+// Chains of a node, bridges are indexed by these
+const (
+	lower_chain = 0
+	upper_chain = 1
+)
+
+// Turn direction of each chain from left to right: left turns for lower, right turns for upper
+var chain_side = [2]int{1, -1}
+
+// Subtrees are rebuilt when a child holds more than this fraction of their leaves
+const dyn_balance = 0.75
+
+// Node of the dynamic hull tree, internal nodes always have two children
+type dyn_node[T Coord] struct {
+	left, right *dyn_node[T]
+	// Point held by a leaf
+	point [2]T
+	// Number of leaves
+	size int
+	// Last leaf point (lexicographically), points up to left.last are routed left
+	last [2]T
+	// Lower and upper bridges between the chains of the children, left end then right end
+	bridge [2][2][2]T
+}
+
+func (n *dyn_node[T]) is_leaf() bool {
+	return n.left == nil
+}
+
+// Inclusive lexicographic bounds on the part of a chain that is on an ancestor's chain
+type chain_bounds[T Coord] struct {
+	lo, hi         [2]T
+	has_lo, has_hi bool
+}
+
+// Direction to descend from node n with bridge p->q, -1 left and 1 right, when p or q is outside
+// the bounds. 0 if both are on the chain (consecutive vertices) and the caller has to decide.
+func (b *chain_bounds[T]) forced(p, q [2]T) int {
+	if b.has_lo && lex_less(p, b.lo) {
+		// Left child's part of the chain is cut off
+		return 1
+	}
+	if b.has_hi && lex_less(b.hi, q) {
+		return -1
+	}
+	return 0
+}
+
+// Descend from a node with bridge p->q in direction dir, updating the bounds
+func (b *chain_bounds[T]) descend(p, q [2]T, dir int) {
+	if dir < 0 && (!b.has_hi || lex_less(p, b.hi)) {
+		b.hi, b.has_hi = p, true
+	}
+	if dir > 0 && (!b.has_lo || lex_less(b.lo, q)) {
+		b.lo, b.has_lo = q, true
+	}
+}
+
+// Vertex of n's chain k touched by the tangent from y, which is before every point of n
+func dyn_tangent[T Coord](n *dyn_node[T], y [2]T, k int) [2]T {
+	var bounds chain_bounds[T]
+	for !n.is_leaf() {
+		p, q := n.bridge[k][0], n.bridge[k][1]
+		dir := bounds.forced(p, q)
+		if dir == 0 {
+			// q is at least as far out as p seen from y, of collinear ends the farther one is the tangent
+			dir = -1
+			if chain_side[k]*orient(y, p, q) <= 0 {
+				dir = 1
+			}
+		}
+		bounds.descend(p, q, dir)
+		if dir < 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return n.point
+}
+
+// Bridge on chain k between the chains of left and right, every point of left is before right
+func dyn_bridge[T Coord](left, right *dyn_node[T], k int) [2][2]T {
+	// Search left's chain for the vertex x where the chain meets the bridge: every vertex up to x
+	// turns the right way towards its own tangent on the right chain, and no vertex after it does
+	n := left
+	var bounds chain_bounds[T]
+	for !n.is_leaf() {
+		p, q := n.bridge[k][0], n.bridge[k][1]
+		dir := bounds.forced(p, q)
+		if dir == 0 {
+			dir = -1
+			if chain_side[k]*orient(p, q, dyn_tangent(right, q, k)) > 0 {
+				dir = 1
+			}
+		}
+		bounds.descend(p, q, dir)
+		if dir < 0 {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return [2][2]T{n.point, dyn_tangent(right, n.point, k)}
+}
+
+// Recompute n's size, last point and bridges from its children
+func (n *dyn_node[T]) update() {
+	n.size = n.left.size + n.right.size
+	n.last = n.right.last
+	for k := range n.bridge {
+		n.bridge[k] = dyn_bridge(n.left, n.right, k)
+	}
+}
+
+func new_leaf[T Coord](p [2]T) *dyn_node[T] {
+	return &dyn_node[T]{point: p, size: 1, last: p}
+}
+
+// Internal node with children left and right
+func join_nodes[T Coord](left, right *dyn_node[T]) *dyn_node[T] {
+	n := &dyn_node[T]{left: left, right: right}
+	n.update()
+	return n
+}
+
+// Append the leaves of n to leaves in order
+func (n *dyn_node[T]) leaves(leaves []*dyn_node[T]) []*dyn_node[T] {
+	if n.is_leaf() {
+		return append(leaves, n)
+	}
+	return n.right.leaves(n.left.leaves(leaves))
+}
+
+// Perfectly balanced tree over leaves
+func build_nodes[T Coord](leaves []*dyn_node[T]) *dyn_node[T] {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	mid := len(leaves) / 2
+	return join_nodes(build_nodes(leaves[:mid]), build_nodes(leaves[mid:]))
+}
+
+// Update n after one of its children changed, rebuilding it if it is out of balance
+func (n *dyn_node[T]) rebalance() *dyn_node[T] {
+	size := n.left.size + n.right.size
+	if float64(max(n.left.size, n.right.size)) > dyn_balance*float64(size) {
+		return build_nodes(n.leaves(make([]*dyn_node[T], 0, size)))
+	}
+	n.update()
+	return n
+}
+
+// Insert p, which is not in the tree yet, under n
+func (n *dyn_node[T]) insert(p [2]T) *dyn_node[T] {
+	if n.is_leaf() {
+		if lex_less(p, n.point) {
+			return join_nodes(new_leaf(p), n)
+		}
+		return join_nodes(n, new_leaf(p))
+	}
+	if lex_less(n.left.last, p) {
+		n.right = n.right.insert(p)
+	} else {
+		n.left = n.left.insert(p)
+	}
+	return n.rebalance()
+}
+
+// Remove p, which is in the tree, from under n. Returns nil if n was the leaf holding p.
+func (n *dyn_node[T]) remove(p [2]T) *dyn_node[T] {
+	if n.is_leaf() {
+		return nil
+	}
+	if lex_less(n.left.last, p) {
+		n.right = n.right.remove(p)
+		if n.right == nil {
+			return n.left
+		}
+	} else {
+		n.left = n.left.remove(p)
+		if n.left == nil {
+			return n.right
+		}
+	}
+	return n.rebalance()
+}
+
+// Append the vertices of n's chain k within bounds to out, in lexicographic order
+func (n *dyn_node[T]) chain(k int, bounds chain_bounds[T], out [][2]T) [][2]T {
+	if n.is_leaf() {
+		if bounds.forced(n.point, n.point) == 0 {
+			out = append(out, n.point)
+		}
+		return out
+	}
+	p, q := n.bridge[k][0], n.bridge[k][1]
+	if !bounds.has_lo || !lex_less(p, bounds.lo) {
+		left := bounds
+		left.descend(p, q, -1)
+		out = n.left.chain(k, left, out)
+	}
+	if !bounds.has_hi || !lex_less(bounds.hi, q) {
+		right := bounds
+		right.descend(p, q, 1)
+		out = n.right.chain(k, right, out)
+	}
+	return out
+}
+
+// DynamicHull is the convex hull of a set of points that can be both added to and removed from.
+// Updates take O(log^3 n) time and the hull can be read at any time in O(h log n). Duplicate
+// points are counted, so a point stays until every copy is removed. A DynamicHull is not safe for
+// concurrent use.
+type DynamicHull[T Coord] struct {
+	root *dyn_node[T]
+	// Copies of each distinct point in the set
+	count map[[2]T]int
+	size  int
+}
+
+// NewDynamicHull returns an empty DynamicHull
+func NewDynamicHull[T Coord]() *DynamicHull[T] {
+	return &DynamicHull[T]{count: make(map[[2]T]int)}
+}
+
+// Add adds p to the set
+func (h *DynamicHull[T]) Add(p [2]T) {
+	h.size++
+	h.count[p]++
+	if h.count[p] > 1 {
+		return
+	}
+	if h.root == nil {
+		h.root = new_leaf(p)
+	} else {
+		h.root = h.root.insert(p)
+	}
+}
+
+// Remove removes one copy of p from the set, returns false if p is not in the set
+func (h *DynamicHull[T]) Remove(p [2]T) bool {
+	c, ok := h.count[p]
+	if !ok {
+		return false
+	}
+	h.size--
+	if c > 1 {
+		h.count[p] = c - 1
+		return true
+	}
+	delete(h.count, p)
+	h.root = h.root.remove(p)
+	return true
+}
+
+// Size returns the number of points in the set, counting duplicates
+func (h *DynamicHull[T]) Size() int {
+	return h.size
+}
+
+// Vertices returns the current hull in canonical form (counterclockwise from the lowest, then
+// leftmost vertex, without collinear boundary points)
+func (h *DynamicHull[T]) Vertices() [][2]T {
+	if h.root == nil {
+		return [][2]T{}
+	}
+	vertices := h.root.chain(lower_chain, chain_bounds[T]{}, nil)
+	if len(vertices) <= 1 {
+		return vertices
+	}
+	// Upper chain right to left, its ends are already the ends of the lower chain
+	upper := h.root.chain(upper_chain, chain_bounds[T]{}, nil)
+	for i := len(upper) - 2; i >= 1; i-- {
+		vertices = append(vertices, upper[i])
+	}
+
+	// Rotate to start from the lowest vertex
+	start := lowest_leftmost(vertices)
+	return append(vertices[start:len(vertices):len(vertices)], vertices[:start]...)
+}
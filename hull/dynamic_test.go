@@ -0,0 +1,155 @@
+package hull
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// Hull of points from scratch with seq_graham_scan, in canonical form
+func graham_reference[T Coord](t *testing.T, points [][2]T) [][2]T {
+	t.Helper()
+	points_copy := make([][2]T, len(points))
+	copy(points_copy, points)
+	hull, err := seq_graham_scan(context.Background(), points_copy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return canonical_hull(hull, false)
+}
+
+func equal_hulls[T Coord](a, b [][2]T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Run random adds and removes on a DynamicHull and check its hull after every operation.
+// gen makes a new point, small grids give plenty of duplicates and collinear points.
+func check_dynamic[T Coord](t *testing.T, r *rand.Rand, ops int, gen func() [2]T) {
+	t.Helper()
+	h := NewDynamicHull[T]()
+	var points [][2]T
+	for op := 0; op < ops; op++ {
+		// Grow for a while, then shrink back to empty
+		if len(points) > 0 && (op > ops/2 || r.Intn(3) == 0) {
+			i := r.Intn(len(points))
+			if !h.Remove(points[i]) {
+				t.Fatalf("op %d: remove %v reported missing", op, points[i])
+			}
+			points[i] = points[len(points)-1]
+			points = points[:len(points)-1]
+		} else {
+			p := gen()
+			h.Add(p)
+			points = append(points, p)
+		}
+
+		if h.Size() != len(points) {
+			t.Fatalf("op %d: size %d, want %d", op, h.Size(), len(points))
+		}
+		got := h.Vertices()
+		want := graham_reference(t, points)
+		if !equal_hulls(got, want) {
+			t.Fatalf("op %d: hull of %v\ngot  %v\nwant %v", op, points, got, want)
+		}
+	}
+}
+
+func TestDynamicHullUniform(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		check_dynamic(t, r, 400, func() [2]float64 {
+			return [2]float64{r.Float64(), r.Float64()}
+		})
+	}
+}
+
+func TestDynamicHullGrid(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, size := range []int{1, 2, 3, 5, 16} {
+		for trial := 0; trial < 20; trial++ {
+			check_dynamic(t, r, 300, func() [2]int32 {
+				return [2]int32{int32(r.Intn(size)), int32(r.Intn(size))}
+			})
+		}
+	}
+}
+
+func TestDynamicHullCollinear(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for trial := 0; trial < 20; trial++ {
+		// Points on a line, and on a vertical line
+		check_dynamic(t, r, 200, func() [2]int64 {
+			x := r.Int63n(50)
+			return [2]int64{x, 3*x - 7}
+		})
+		check_dynamic(t, r, 200, func() [2]int64 {
+			return [2]int64{4, r.Int63n(50)}
+		})
+	}
+}
+
+func TestDynamicHullConvexPosition(t *testing.T) {
+	// Every point is on the hull, so removals keep exposing different bridges
+	r := rand.New(rand.NewSource(4))
+	for trial := 0; trial < 5; trial++ {
+		check_dynamic(t, r, 600, func() [2]int64 {
+			return parabola_point(r.Intn(1 << 12))
+		})
+	}
+}
+
+// Point i on one of two parabolas facing each other, which together are in convex position
+func parabola_point(i int) [2]int64 {
+	x := int64(i/2 - 1<<10)
+	if i%2 == 0 {
+		return [2]int64{x, x * x}
+	}
+	return [2]int64{x, 1<<22 - x*x}
+}
+
+func TestDynamicHullRemoveMissing(t *testing.T) {
+	h := NewDynamicHull[float32]()
+	if h.Remove([2]float32{1, 2}) {
+		t.Fatal("removed a point from an empty hull")
+	}
+	h.Add([2]float32{1, 2})
+	h.Add([2]float32{1, 2})
+	if !h.Remove([2]float32{1, 2}) || !h.Remove([2]float32{1, 2}) {
+		t.Fatal("could not remove both copies")
+	}
+	if h.Remove([2]float32{1, 2}) || len(h.Vertices()) != 0 {
+		t.Fatal("point still in the set after removing every copy")
+	}
+}
+
+// Adding points and removing them all leaves an empty hull, not a nil one, like Hull
+func TestDynamicHullEmptied(t *testing.T) {
+	h := NewDynamicHull[int32]()
+	if hull := h.Vertices(); hull == nil || len(hull) != 0 {
+		t.Fatalf("new hull: got %#v, want an empty hull", hull)
+	}
+	r := rand.New(rand.NewSource(5))
+	var points [][2]int32
+	for i := 0; i < 200; i++ {
+		p := [2]int32{int32(r.Intn(20)), int32(r.Intn(20))}
+		h.Add(p)
+		points = append(points, p)
+	}
+	r.Shuffle(len(points), func(i, j int) { points[i], points[j] = points[j], points[i] })
+	for _, p := range points {
+		if !h.Remove(p) {
+			t.Fatalf("could not remove %v", p)
+		}
+	}
+	if hull := h.Vertices(); hull == nil || len(hull) != 0 || h.Size() != 0 {
+		t.Fatalf("after removing every point: got %#v with size %d, want an empty hull", hull, h.Size())
+	}
+}
@@ -0,0 +1,308 @@
+package hull
+
+import (
+	"math"
+	"math/rand"
+)
+
+/**********************
+ * Enclosing shapes   *
+ **********************
+Smallest circle and ellipse containing a set of points. Both only depend on the hull vertices, so on
+large inputs they are meant to run on the output of a hull algorithm rather than on every point.
+
+The circle is Welzl's algorithm in its iterative form: add points in random order, and whenever one
+is outside the current circle it must be on the boundary of the new one, so restart with it fixed
+(then with two points fixed). Expected O(h).
+
+The ellipse is Khachiyan's algorithm with the away steps of Todd and Yildirim. Points are weighted,
+the weights give a covariance matrix, and each step moves weight towards the point farthest outside
+the ellipse it defines (or away from the one most inside), converging to the minimum-area ellipse
+whose boundary holds the points with positive weight. Once no point is more than 1 + tolerance
+outside (in the lifted norm) the area is within that factor of the minimum (Khachiyan), and the
+ellipse is scaled so it contains every point.
+*/
+
+// Points this close to a circle, relative to its radius, are on it
+const circle_slack = 1e-12
+
+// Ellipse tolerance used when the tolerance passed is not positive
+const DefaultEllipseTolerance = 1e-4
+
+// Circle is a circle, with the indices of the points on it that determine it (1 to 3 of them)
+type Circle struct {
+	Center  [2]float64
+	Radius  float64
+	Support []int
+}
+
+// Ellipse is the set of x with (x - Center)ᵀ Matrix (x - Center) <= 1
+type Ellipse struct {
+	Center [2]float64
+	// Symmetric positive definite
+	Matrix [2][2]float64
+	// Semi-axis lengths, major first, and the angle of the major axis from the x axis in radians
+	Axes  [2]float64
+	Angle float64
+	// Indices of the points on the boundary that determine the ellipse
+	Support []int
+}
+
+// SmallestCircle returns the smallest circle containing points. Any points work, but it only depends
+// on the hull, so pass hull vertices for speed. Expected O(n)
+func SmallestCircle[T Coord](points [][2]T) (Circle, error) {
+	if len(points) == 0 {
+		return Circle{}, ErrEmptyPolygon
+	}
+	// Work relative to the first point, which keeps float64 error proportional to the extent
+	origin := points[0]
+	rel := func(i int) [2]float64 {
+		return [2]float64{float64(points[i][0]) - float64(origin[0]), float64(points[i][1]) - float64(origin[1])}
+	}
+
+	order := rand.New(rand.NewSource(1)).Perm(len(points))
+	c := point_circle(order[0], rel(order[0]))
+	for i := 1; i < len(order); i++ {
+		pi := rel(order[i])
+		if c.contains(pi) {
+			continue
+		}
+		c = point_circle(order[i], pi)
+		for j := 0; j < i; j++ {
+			pj := rel(order[j])
+			if c.contains(pj) {
+				continue
+			}
+			c = diameter_circle(order[i], order[j], pi, pj)
+			for k := 0; k < j; k++ {
+				pk := rel(order[k])
+				if c.contains(pk) {
+					continue
+				}
+				if orient(points[order[i]], points[order[j]], points[order[k]]) == 0 {
+					// On a line, the outer two are a diameter
+					c = diameter_circle(order[i], order[k], pi, pk)
+					if !c.contains(pj) {
+						c = diameter_circle(order[j], order[k], pj, pk)
+					}
+					continue
+				}
+				c = circumcircle(order[i], order[j], order[k], pi, pj, pk)
+			}
+		}
+	}
+	c.Center[0] += float64(origin[0])
+	c.Center[1] += float64(origin[1])
+	return c, nil
+}
+
+func (c *Circle) contains(p [2]float64) bool {
+	return math.Hypot(p[0]-c.Center[0], p[1]-c.Center[1]) <= c.Radius*(1+circle_slack)
+}
+
+func point_circle(i int, p [2]float64) Circle {
+	return Circle{Center: p, Support: []int{i}}
+}
+
+// Circle with diameter pq
+func diameter_circle(i, j int, p, q [2]float64) Circle {
+	center := [2]float64{(p[0] + q[0]) / 2, (p[1] + q[1]) / 2}
+	return Circle{Center: center, Radius: math.Hypot(p[0]-q[0], p[1]-q[1]) / 2, Support: []int{i, j}}
+}
+
+// Circle through p, q and r, which are not collinear
+func circumcircle(i, j, k int, p, q, r [2]float64) Circle {
+	bx, by := q[0]-p[0], q[1]-p[1]
+	cx, cy := r[0]-p[0], r[1]-p[1]
+	d := 2 * (bx*cy - by*cx)
+	b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+	ux, uy := (cy*b2-by*c2)/d, (bx*c2-cx*b2)/d
+	// The radius is the farthest of the three, so all three are on or in the circle despite rounding
+	center := [2]float64{p[0] + ux, p[1] + uy}
+	radius := math.Max(math.Hypot(ux, uy), math.Max(
+		math.Hypot(q[0]-center[0], q[1]-center[1]), math.Hypot(r[0]-center[0], r[1]-center[1])))
+	return Circle{Center: center, Radius: radius, Support: []int{i, j, k}}
+}
+
+// SmallestEllipse returns an ellipse containing points whose area is within a factor of 1 + tolerance
+// of the minimum (DefaultEllipseTolerance if tolerance <= 0). Like SmallestCircle it only
+// depends on the hull, so pass hull vertices. Points on a line have no such ellipse and return
+// ErrDegenerate, as do points too close to a line to tell apart in float64.
+func SmallestEllipse[T Coord](points [][2]T, tolerance float64) (Ellipse, error) {
+	if len(points) == 0 {
+		return Ellipse{}, ErrEmptyPolygon
+	}
+	if collinear(points) {
+		return Ellipse{}, ErrDegenerate
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultEllipseTolerance
+	}
+
+	// Centre on the mean and scale to about unit size for conditioning
+	m := len(points)
+	var mean [2]float64
+	for _, p := range points {
+		mean[0] += float64(p[0]) / float64(m)
+		mean[1] += float64(p[1]) / float64(m)
+	}
+	scale := 0.0
+	for _, p := range points {
+		scale = math.Max(scale, math.Max(math.Abs(float64(p[0])-mean[0]), math.Abs(float64(p[1])-mean[1])))
+	}
+	pts := make([][2]float64, m)
+	for i, p := range points {
+		pts[i] = [2]float64{(float64(p[0]) - mean[0]) / scale, (float64(p[1]) - mean[1]) / scale}
+	}
+
+	// Weights u and the lifted moment matrix X = sum u_i (p_i, 1)(p_i, 1)ᵀ
+	const d = 2
+	u := make([]float64, m)
+	for i := range u {
+		u[i] = 1 / float64(m)
+	}
+	// Lifted squared distances (p_i, 1)ᵀ X⁻¹ (p_i, 1), d + 1 on the ellipse
+	dists := make([]float64, m)
+	update := func() (far, near float64, hi, lo int, ok bool) {
+		var x [3][3]float64
+		for i, p := range pts {
+			q := [3]float64{p[0], p[1], 1}
+			for r := 0; r < 3; r++ {
+				for c := 0; c < 3; c++ {
+					x[r][c] += u[i] * q[r] * q[c]
+				}
+			}
+		}
+		inv, ok := invert3(x)
+		if !ok {
+			return 0, 0, 0, 0, false
+		}
+		hi, lo = 0, -1
+		for i, p := range pts {
+			q := [3]float64{p[0], p[1], 1}
+			s := 0.0
+			for r := 0; r < 3; r++ {
+				for c := 0; c < 3; c++ {
+					s += q[r] * inv[r][c] * q[c]
+				}
+			}
+			dists[i] = s
+			if s > dists[hi] {
+				hi = i
+			}
+			if u[i] > 0 && (lo == -1 || s < dists[lo]) {
+				lo = i
+			}
+		}
+		return dists[hi], dists[lo], hi, lo, true
+	}
+	for {
+		far, near, hi, lo, ok := update()
+		if !ok {
+			// Not on a line, but too close to one for float64
+			return Ellipse{}, ErrDegenerate
+		}
+		if far <= (1+tolerance)*(d+1) {
+			break
+		}
+		if far-(d+1) > (d+1)-near {
+			// Move weight towards the farthest point
+			step := (far - d - 1) / ((d + 1) * (far - 1))
+			for i := range u {
+				u[i] *= 1 - step
+			}
+			u[hi] += step
+		} else {
+			// Move weight away from the nearest point, dropping it if the step reaches it
+			step := (near - d - 1) / ((d + 1) * (near - 1))
+			step = math.Max(step, -u[lo]/(1-u[lo]))
+			for i := range u {
+				u[i] *= 1 - step
+			}
+			u[lo] += step
+			if u[lo] < 0 {
+				u[lo] = 0
+			}
+		}
+	}
+
+	// Centre c = sum u_i p_i, shape (sum u_i (p_i - c)(p_i - c)ᵀ)⁻¹ / d
+	var c [2]float64
+	for i, p := range pts {
+		c[0] += u[i] * p[0]
+		c[1] += u[i] * p[1]
+	}
+	var cov [2][2]float64
+	var support []int
+	for i, p := range pts {
+		if u[i] > 0 {
+			support = append(support, i)
+		}
+		dx, dy := p[0]-c[0], p[1]-c[1]
+		cov[0][0] += u[i] * dx * dx
+		cov[0][1] += u[i] * dx * dy
+		cov[1][1] += u[i] * dy * dy
+	}
+	det := cov[0][0]*cov[1][1] - cov[0][1]*cov[0][1]
+	if !(det > 0) {
+		return Ellipse{}, ErrDegenerate
+	}
+	a := [2][2]float64{{cov[1][1] / det / d, -cov[0][1] / det / d}, {-cov[0][1] / det / d, cov[0][0] / det / d}}
+	// Grow to contain every point exactly
+	grow := 0.0
+	for _, p := range pts {
+		dx, dy := p[0]-c[0], p[1]-c[1]
+		grow = math.Max(grow, a[0][0]*dx*dx+2*a[0][1]*dx*dy+a[1][1]*dy*dy)
+	}
+
+	// Back to input coordinates
+	res := Ellipse{Support: support}
+	res.Center = [2]float64{mean[0] + c[0]*scale, mean[1] + c[1]*scale}
+	for r := 0; r < 2; r++ {
+		for col := 0; col < 2; col++ {
+			res.Matrix[r][col] = a[r][col] / grow / (scale * scale)
+		}
+	}
+	// Eigenvalues of the matrix are 1 / axis², the smaller one is the major axis
+	ma, mb, mc := res.Matrix[0][0], res.Matrix[0][1], res.Matrix[1][1]
+	mid, half := (ma+mc)/2, math.Hypot((ma-mc)/2, mb)
+	res.Axes = [2]float64{1 / math.Sqrt(mid-half), 1 / math.Sqrt(mid+half)}
+	res.Angle = math.Atan2(-2*mb, mc-ma) / 2
+	return res, nil
+}
+
+// Whether every point is on one line
+func collinear[T Coord](points [][2]T) bool {
+	a := points[0]
+	b := a
+	for _, p := range points {
+		if b == a {
+			b = p
+		} else if orient(a, b, p) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Inverse of a 3x3 matrix by cofactors, if its determinant is positive
+func invert3(m [3][3]float64) ([3][3]float64, bool) {
+	var inv [3][3]float64
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			r1, r2 := (c+1)%3, (c+2)%3
+			c1, c2 := (r+1)%3, (r+2)%3
+			inv[r][c] = m[r1][c1]*m[r2][c2] - m[r1][c2]*m[r2][c1]
+		}
+	}
+	det := m[0][0]*inv[0][0] + m[0][1]*inv[1][0] + m[0][2]*inv[2][0]
+	if !(det > 0) {
+		return inv, false
+	}
+	for r := range inv {
+		for c := range inv[r] {
+			inv[r][c] /= det
+		}
+	}
+	return inv, true
+}
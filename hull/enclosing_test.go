@@ -0,0 +1,204 @@
+package hull
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Check that c contains every point and goes through its support
+func check_circle[T Coord](t *testing.T, points [][2]T, c Circle) {
+	t.Helper()
+	slack := 1e-9 * math.Max(c.Radius, 1)
+	for i, p := range points {
+		if d := math.Hypot(float64(p[0])-c.Center[0], float64(p[1])-c.Center[1]); d > c.Radius+slack {
+			t.Fatalf("circle %v radius %g: point %d %v is outside at distance %g", c.Center, c.Radius, i, p, d)
+		}
+	}
+	if len(c.Support) < 1 || len(c.Support) > 3 {
+		t.Fatalf("circle %v radius %g: %d support points", c.Center, c.Radius, len(c.Support))
+	}
+	for _, i := range c.Support {
+		p := points[i]
+		if d := math.Hypot(float64(p[0])-c.Center[0], float64(p[1])-c.Center[1]); math.Abs(d-c.Radius) > slack {
+			t.Fatalf("circle %v radius %g: support %v is at distance %g", c.Center, c.Radius, p, d)
+		}
+	}
+}
+
+// Radius of the smallest circle containing points, from every circle through two or three of them
+func brute_force_radius[T Coord](points [][2]T) float64 {
+	best := math.Inf(1)
+	try := func(c Circle) {
+		for _, p := range points {
+			if math.Hypot(float64(p[0])-c.Center[0], float64(p[1])-c.Center[1]) > c.Radius*(1+1e-9) {
+				return
+			}
+		}
+		best = math.Min(best, c.Radius)
+	}
+	f := func(i int) [2]float64 { return [2]float64{float64(points[i][0]), float64(points[i][1])} }
+	for i := range points {
+		try(point_circle(i, f(i)))
+		for j := i + 1; j < len(points); j++ {
+			try(diameter_circle(i, j, f(i), f(j)))
+			for k := j + 1; k < len(points); k++ {
+				if orient(points[i], points[j], points[k]) != 0 {
+					try(circumcircle(i, j, k, f(i), f(j), f(k)))
+				}
+			}
+		}
+	}
+	return best
+}
+
+// Check that e contains every point, and return its area
+func check_ellipse[T Coord](t *testing.T, points [][2]T, e Ellipse) float64 {
+	t.Helper()
+	m := e.Matrix
+	if m[0][1] != m[1][0] || !(m[0][0] > 0) || !(m[0][0]*m[1][1]-m[0][1]*m[1][0] > 0) {
+		t.Fatalf("ellipse matrix %v is not symmetric positive definite", m)
+	}
+	for i, p := range points {
+		dx, dy := float64(p[0])-e.Center[0], float64(p[1])-e.Center[1]
+		if v := m[0][0]*dx*dx + 2*m[0][1]*dx*dy + m[1][1]*dy*dy; v > 1+1e-9 {
+			t.Fatalf("ellipse %+v: point %d %v is outside (%g)", e, i, p, v)
+		}
+	}
+	// The axes are the matrix's eigenvalues, so they give its determinant
+	if det := m[0][0]*m[1][1] - m[0][1]*m[1][0]; math.Abs(det*math.Pow(e.Axes[0]*e.Axes[1], 2)-1) > 1e-9 || e.Axes[0] < e.Axes[1] {
+		t.Fatalf("ellipse %+v: axes don't match the matrix", e)
+	}
+	return math.Pi * e.Axes[0] * e.Axes[1]
+}
+
+func TestSmallestCircleBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		points := random_points[int64](r, 1+r.Intn(20))
+		if trial%2 == 0 {
+			// Plenty of duplicates and collinear points
+			for i := range points {
+				points[i][0] %= 4
+				points[i][1] %= 4
+			}
+		}
+		c, err := SmallestCircle(points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_circle(t, points, c)
+		if want := brute_force_radius(points); math.Abs(c.Radius-want) > 1e-9*math.Max(want, 1) {
+			t.Fatalf("circle of %v: radius %g, want %g", points, c.Radius, want)
+		}
+	}
+}
+
+func TestSmallestCircleCases(t *testing.T) {
+	h := math.Sqrt(3)
+	tests := []struct {
+		name   string
+		points [][2]float64
+		center [2]float64
+		radius float64
+	}{
+		{"one point", [][2]float64{{2, 3}}, [2]float64{2, 3}, 0},
+		{"two points", [][2]float64{{-1, 2}, {5, 10}}, [2]float64{2, 6}, 5},
+		{"equilateral triangle", [][2]float64{{0, 0}, {2, 0}, {1, h}}, [2]float64{1, h / 3}, 2 / h},
+		{"obtuse triangle", [][2]float64{{0, 0}, {4, 0}, {2, 1}}, [2]float64{2, 0}, 2},
+		{"collinear", [][2]float64{{1, 1}, {3, 3}, {0, 0}, {2, 2}}, [2]float64{1.5, 1.5}, 1.5 * math.Sqrt(2)},
+		{"square with center", [][2]float64{{0, 0}, {2, 0}, {1, 1}, {2, 2}, {0, 2}}, [2]float64{1, 1}, math.Sqrt(2)},
+	}
+	for _, test := range tests {
+		c, err := SmallestCircle(test.points)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		check_circle(t, test.points, c)
+		if math.Hypot(c.Center[0]-test.center[0], c.Center[1]-test.center[1]) > 1e-9 || math.Abs(c.Radius-test.radius) > 1e-9 {
+			t.Fatalf("%s: got center %v radius %g, want %v radius %g", test.name, c.Center, c.Radius, test.center, test.radius)
+		}
+	}
+	if _, err := SmallestCircle([][2]int64{}); !errors.Is(err, ErrEmptyPolygon) {
+		t.Fatalf("no points: got %v, want %v", err, ErrEmptyPolygon)
+	}
+}
+
+func TestSmallestEllipseCases(t *testing.T) {
+	h := math.Sqrt(3)
+	tests := []struct {
+		name   string
+		points [][2]float64
+		center [2]float64
+		// Major and minor semi-axes, and the major axis' angle (mod pi)
+		axes  [2]float64
+		angle float64
+	}{
+		// Affine images of a square, whose smallest ellipse is its circumcircle
+		{"axis-aligned rectangle", [][2]float64{{-3, -1}, {3, -1}, {3, 1}, {-3, 1}}, [2]float64{0, 0}, [2]float64{3 * math.Sqrt2, math.Sqrt2}, 0},
+		{"tall rectangle", [][2]float64{{1, 0}, {3, 0}, {3, 8}, {1, 8}, {2, 4}}, [2]float64{2, 4}, [2]float64{4 * math.Sqrt2, math.Sqrt2}, math.Pi / 2},
+		{"axis-aligned rhombus", [][2]float64{{5, 0}, {0, 2}, {-5, 0}, {0, -2}}, [2]float64{0, 0}, [2]float64{5, 2}, 0},
+		// Its own smallest ellipse by symmetry, and so the circumcircle
+		{"equilateral triangle", [][2]float64{{0, 0}, {2, 0}, {1, h}}, [2]float64{1, h / 3}, [2]float64{2 / h, 2 / h}, 0},
+	}
+	for _, test := range tests {
+		for _, tolerance := range []float64{0, 1e-9} {
+			e, err := SmallestEllipse(test.points, tolerance)
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			area, want := check_ellipse(t, test.points, e), math.Pi*test.axes[0]*test.axes[1]
+			if tolerance == 0 {
+				tolerance = DefaultEllipseTolerance
+			}
+			if area < want*(1-1e-9) || area > want*(1+tolerance) {
+				t.Fatalf("%s tolerance %g: area %g, want %g", test.name, tolerance, area, want)
+			}
+			if tolerance > 1e-6 {
+				continue
+			}
+			angle := math.Remainder(e.Angle-test.angle, math.Pi)
+			if math.Hypot(e.Center[0]-test.center[0], e.Center[1]-test.center[1]) > 1e-3 ||
+				math.Abs(e.Axes[0]-test.axes[0]) > 1e-3 || math.Abs(e.Axes[1]-test.axes[1]) > 1e-3 ||
+				(test.axes[0] != test.axes[1] && math.Abs(angle) > 1e-3) {
+				t.Fatalf("%s: got center %v axes %v angle %g, want %v %v %g", test.name, e.Center, e.Axes, e.Angle, test.center, test.axes, test.angle)
+			}
+		}
+	}
+
+	if _, err := SmallestEllipse([][2]int64{}, 0); !errors.Is(err, ErrEmptyPolygon) {
+		t.Fatalf("no points: got %v, want %v", err, ErrEmptyPolygon)
+	}
+	for _, points := range [][][2]int64{{{1, 1}}, {{1, 1}, {1, 1}}, {{1, 1}, {3, 2}}, {{0, 0}, {2, 2}, {1, 1}, {5, 5}}} {
+		if _, err := SmallestEllipse(points, 0); !errors.Is(err, ErrDegenerate) {
+			t.Fatalf("%v: got %v, want %v", points, err, ErrDegenerate)
+		}
+	}
+}
+
+func TestSmallestEllipseRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 100; trial++ {
+		points := make([][2]float64, 3+r.Intn(50))
+		// Stretched and turned, so the ellipse is far from a circle
+		stretch, turn := 1+10*r.Float64(), r.Float64()*math.Pi
+		for i := range points {
+			x, y := stretch*r.NormFloat64(), r.NormFloat64()
+			points[i] = [2]float64{x*math.Cos(turn) - y*math.Sin(turn), x*math.Sin(turn) + y*math.Cos(turn)}
+		}
+		e, err := SmallestEllipse(points, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		area := check_ellipse(t, points, e)
+		// The smallest circle is an ellipse too
+		c, err := SmallestCircle(points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if circle := math.Pi * c.Radius * c.Radius; area > circle*(1+DefaultEllipseTolerance) {
+			t.Fatalf("ellipse area %g is larger than the smallest circle's %g", area, circle)
+		}
+	}
+}
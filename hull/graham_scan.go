@@ -0,0 +1,199 @@
+package hull
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+const PAR_QUICKSORT_LIMIT int = 2000
+
+// Slices this short are insertion sorted
+const QSORT_INSERTION_LIMIT int = 16
+
+// Parallel quicksort (see qsort), stops partitioning once ctx is cancelled
+func parallel_qsort[T Coord](ctx context.Context, a [][2]T, cmp func([2]T, [2]T) bool, wg *sync.WaitGroup) {
+	if len(a) >= 2 {
+		if done(ctx) {
+			wg.Done()
+			return
+		}
+		lt, gt := partition3(ctx, a, median_of_three(a, cmp), cmp)
+
+		// Spawn new goroutines if enough elements remaining
+		if len(a) > PAR_QUICKSORT_LIMIT {
+			wg.Add(2)
+			go parallel_qsort(ctx, a[:lt], cmp, wg)
+			go parallel_qsort(ctx, a[gt:], cmp, wg)
+
+		} else {
+			qsort(ctx, a[:lt], cmp)
+			qsort(ctx, a[gt:], cmp)
+		}
+	}
+	wg.Done()
+}
+
+// Quicksort adapted from https://stackoverflow.com/a/55267961/15471686, stops once ctx is cancelled
+func qsort[T Coord](ctx context.Context, a [][2]T, cmp func([2]T, [2]T) bool) {
+	// Recurse into the smaller side and loop on the larger, so the stack stays O(log n) deep
+	for len(a) > QSORT_INSERTION_LIMIT {
+		if done(ctx) {
+			return
+		}
+		// Three way partition so sorted input and runs of equal points still split evenly
+		lt, gt := partition3(ctx, a, median_of_three(a, cmp), cmp)
+		if lt < len(a)-gt {
+			qsort(ctx, a[:lt], cmp)
+			a = a[gt:]
+		} else {
+			qsort(ctx, a[gt:], cmp)
+			a = a[:lt]
+		}
+	}
+	insertion_sort(a, cmp)
+}
+
+// Median of three random points. Fixed positions (first, middle, last) are defeated by the order
+// partitioning leaves some inputs in, random ones make bad splits unlikely for any input.
+func median_of_three[T Coord](a [][2]T, cmp func([2]T, [2]T) bool) [2]T {
+	x, y, z := a[rand.Intn(len(a))], a[rand.Intn(len(a))], a[rand.Intn(len(a))]
+	if cmp(y, x) {
+		x, y = y, x
+	}
+	if cmp(z, y) {
+		y = z
+		if cmp(y, x) {
+			y = x
+		}
+	}
+	return y
+}
+
+// Sort by polar angle using custom quicksort (faster)
+func custom_sort[T Coord](ctx context.Context, a [][2]T, bot_point [2]T, order int, parallel_sort bool) {
+	// Sort by polar angle to bottom most point
+	cmp := func(a, b [2]T) bool {
+		turn := orient(bot_point, a, b)
+		// Break colinear ties using distance
+		if turn == 0 {
+			// Point j should be further than point i
+			return farther(bot_point, b, a)
+		} else {
+			// Point j should be left of point i
+			return order*turn > 0
+		}
+	}
+	if parallel_sort {
+		wg := new(sync.WaitGroup)
+		wg.Add(1)
+		parallel_qsort(ctx, a, cmp, wg)
+		wg.Wait()
+	} else {
+		qsort(ctx, a, cmp)
+	}
+
+}
+
+// Sort by polar angle using Go builtin slice sort (slow)
+func go_sort[T Coord](a [][2]T, bot_point [2]T, order int) {
+	// Sort by polar angle to bottom most point
+	sort.Slice(a, func(i, j int) bool {
+		turn := orient(bot_point, a[i], a[j])
+		// Break colinear ties using distance
+		if turn == 0 {
+			// Point j should be further than point i
+			return farther(bot_point, a[j], a[i])
+		} else {
+			// Point j should be left of point i
+			return order*turn > 0
+		}
+	})
+}
+
+// Graham Scan, returns nil if ctx is cancelled
+func graham_scan_run[T Coord](ctx context.Context, points [][2]T, clockwise, parallel_sort bool) [][2]T {
+	// Set -1 for CW hull, 1 for CCW
+	order := 1
+	if clockwise {
+		order = -1
+	}
+
+	// Get index of bottommost point, leftmost of ties for CCW (rightmost for CW)
+	// so every other point is within [0, pi) of the scan direction
+	bottom := 0
+	for i, point := range points {
+		bot := points[bottom]
+		if point[1] < bot[1] || (point[1] == bot[1] && (order > 0 && point[0] < bot[0] || order < 0 && point[0] > bot[0])) {
+			bottom = i
+		}
+	}
+	bot_point := points[bottom]
+	points[0], points[bottom] = points[bottom], points[0]
+
+	// Sort points based on angle to the bottom point
+	sort_points := points[1:]
+	// debug("started sort")
+	// sort_start := time.Now()
+	custom_sort(ctx, sort_points, bot_point, order, parallel_sort)
+	// go_sort(sort_points, bot_point, order)
+	// debug("finished sort", time.Since(sort_start))
+	if done(ctx) {
+		// Sort may have stopped early
+		return nil
+	}
+
+	// Remove collinear points
+	new_index := 1
+	for i := 1; i < len(points); i++ {
+		for i < len(points)-1 && orient(bot_point, points[i], points[i+1]) == 0 {
+			i++
+		}
+		points[new_index] = points[i]
+		new_index++
+	}
+	points = points[:new_index]
+
+	// Iterate through points with Graham scan
+	stack := make([]int, 0, len(points)/4)
+	for i := 0; i < len(points); i++ {
+		// Pop off stack if new point makes a clockwise turn
+		for len(stack) > 1 && order*orient(points[stack[len(stack)-2]], points[stack[len(stack)-1]], points[i]) <= 0 {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, i)
+	}
+
+	// Stack now contains indices of convex hull
+	var hull [][2]T = [][2]T{}
+	for _, i := range stack {
+		hull = append(hull, points[i])
+	}
+
+	return hull
+}
+
+// Run graham scan, hull is returned clockwise
+func seq_graham_scan[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+	if len(points) < 3 {
+		return points, nil
+	}
+	hull := graham_scan_run(ctx, points, true, false)
+	if hull == nil {
+		return nil, ctx.Err()
+	}
+	return hull, nil
+}
+
+// Run graham scan with parallel sort, hull is returned clockwise
+func parallel_graham_scan[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+	if len(points) < 3 {
+		return points, nil
+	}
+	hull := graham_scan_run(ctx, points, true, true)
+	if hull == nil {
+		return nil, ctx.Err()
+	}
+	return hull, nil
+}
@@ -0,0 +1,186 @@
+// Package hull implements parallel and sequential 2D convex hull algorithms:
+// Jarvis march, Graham scan, Andrew's monotone chain, Chan's algorithm, Kirkpatrick–Seidel,
+// divide and conquer and Quickhull, and Quickhull in three dimensions (Quickhull3DSerial,
+// Quickhull3DParallel) and up to MaxDimension dimensions (QuickhullDSerial, QuickhullDParallel).
+//
+// Every algorithm returns the hull in the same canonical form: a counterclockwise polygon
+// starting from the lowest (then leftmost) vertex, so results can be compared directly.
+// Every algorithm takes a context and stops with ctx.Err() once it is cancelled.
+//
+// Algorithms are generic over the coordinate type (float32, float64, int32 or int64, see Coord)
+// and their orientation tests are exact for every type, including the full int64 range.
+//
+// RotatingCalipers measures a hull once it is computed: diameter, width and minimum enclosing
+// rectangles. SmallestCircle and SmallestEllipse find the smallest enclosing circle and ellipse.
+// NewLocator preprocesses a hull for point-in-hull and tangent queries. Intersection, UnionHull and
+// MinkowskiSum combine two hulls.
+package hull
+
+import "context"
+
+// CollinearPolicy controls which points on the hull boundary are returned
+type CollinearPolicy int
+
+const (
+	// Only the extreme vertices of the hull
+	CollinearStrict CollinearPolicy = iota
+	// Every distinct input point that lies on the hull boundary, in boundary order
+	CollinearInclusive
+)
+
+// Options configures how a hull is returned
+type Options struct {
+	// Return the hull clockwise (still starting from the lowest, then leftmost vertex)
+	Clockwise bool
+	// Which boundary points to return, CollinearStrict by default
+	Collinear CollinearPolicy
+
+	// Discard points strictly inside a polygon of extreme points before running (see AklToussaint)
+	Prefilter Prefilter
+	// Filled in with statistics about the run if not nil
+	Stats *Stats
+
+	// Parallel Chan's: number of group size guesses to run at once (DefaultSimulIters if <= 0)
+	SimulIters int
+	// Parallel Chan's: reuse subhulls computed by smaller group sizes
+	Coalesce bool
+}
+
+// Number of parallel Chan's iterations run at once unless Options.SimulIters is set
+const DefaultSimulIters = 2
+
+// Options.SimulIters with the default applied
+func (opts Options) simul_iters() int {
+	if opts.SimulIters <= 0 {
+		return DefaultSimulIters
+	}
+	return opts.SimulIters
+}
+
+// A hull algorithm, returns ctx.Err() if cancelled before finishing
+type hull_method[T Coord] func(ctx context.Context, points [][2]T) ([][2]T, error)
+
+// Run an algorithm and put its output in canonical form
+func run[T Coord](ctx context.Context, points [][2]T, opts Options, method hull_method[T]) ([][2]T, error) {
+	// Stats may be reused across runs, only report this one
+	if opts.Stats != nil {
+		*opts.Stats = Stats{}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return [][2]T{}, nil
+	}
+	if opts.Prefilter != PrefilterNone {
+		survivors, eliminated, err := AklToussaint(ctx, points, opts.Prefilter)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Stats != nil {
+			opts.Stats.Eliminated = eliminated
+		}
+		points = survivors
+	}
+	if opts.Collinear != CollinearInclusive {
+		hull, err := method(ctx, points)
+		if err != nil {
+			return nil, err
+		}
+		return canonical_hull(hull, opts.Clockwise), nil
+	}
+
+	// Algorithms may overwrite their input, keep the original points to find the boundary points
+	points_copy := make([][2]T, len(points))
+	copy(points_copy, points)
+	hull, err := method(ctx, points_copy)
+	if err != nil {
+		return nil, err
+	}
+	hull = canonical_hull(hull, false)
+	return canonical_hull_inclusive(boundary_points(points, hull), opts.Clockwise), nil
+}
+
+// SeqJarvis computes the convex hull of points with a sequential Jarvis march
+func SeqJarvis[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, seq_jarvis[T])
+}
+
+// ParallelJarvis computes the convex hull of points with a Jarvis march that wraps
+// from the four extreme points in both directions at once
+func ParallelJarvis[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, parallel_jarvis[T])
+}
+
+// SeqGrahamScan computes the convex hull of points with a sequential Graham scan.
+// points is reordered in place.
+func SeqGrahamScan[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, seq_graham_scan[T])
+}
+
+// ParallelGrahamScan computes the convex hull of points with a Graham scan that
+// sorts in parallel. points is reordered in place.
+func ParallelGrahamScan[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, parallel_graham_scan[T])
+}
+
+// SeqMonotoneChain computes the convex hull of points with Andrew's monotone chain algorithm.
+// points is reordered in place.
+func SeqMonotoneChain[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, seq_monotone_chain[T])
+}
+
+// ParallelMonotoneChain computes the convex hull of points with Andrew's monotone chain algorithm,
+// sorting in parallel. points is reordered in place.
+func ParallelMonotoneChain[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, parallel_monotone_chain[T])
+}
+
+// SeqChans computes the convex hull of points with sequential Chan's algorithm.
+// points is reordered in place.
+func SeqChans[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, seq_chans[T])
+}
+
+// ParallelChans computes the convex hull of points with parallel Chan's algorithm,
+// running opts.SimulIters group size guesses at once (and coalescing subhulls if opts.Coalesce)
+func ParallelChans[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, func(ctx context.Context, points [][2]T) ([][2]T, error) {
+		return parallel_chans(ctx, points, opts.simul_iters(), opts.Coalesce)
+	})
+}
+
+// SeqKirkpatrickSeidel computes the convex hull of points with the Kirkpatrick–Seidel
+// (marriage before conquest) algorithm
+func SeqKirkpatrickSeidel[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, seq_kirkpatrick_seidel[T])
+}
+
+// ParallelKirkpatrickSeidel computes the convex hull of points with the Kirkpatrick–Seidel
+// algorithm, recursing on each side of every bridge in a new goroutine
+func ParallelKirkpatrickSeidel[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, parallel_kirkpatrick_seidel[T])
+}
+
+// SeqDivideConquer computes the convex hull of points by sorting them, hulling each half recursively
+// and merging the halves along their upper and lower bridges
+func SeqDivideConquer[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, seq_divide_conquer[T])
+}
+
+// ParallelDivideConquer computes the convex hull of points by divide and conquer, hulling the
+// halves of large subproblems in new goroutines
+func ParallelDivideConquer[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, parallel_divide_conquer[T])
+}
+
+// QuickhullSerial computes the convex hull of points with sequential Quickhull
+func QuickhullSerial[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, quickhull_serial[T])
+}
+
+// QuickhullParallel computes the convex hull of points with Quickhull, recursing
+// on each side of the dividing line in a new goroutine
+func QuickhullParallel[T Coord](ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return run(ctx, points, opts, quickhull_parallel[T])
+}
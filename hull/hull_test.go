@@ -0,0 +1,48 @@
+package hull
+
+import (
+	"context"
+	"testing"
+)
+
+// Every registered algorithm returns an empty hull for no points, in every mode
+func TestEmptyInput(t *testing.T) {
+	modes := []Options{
+		{},
+		{Clockwise: true},
+		{Collinear: CollinearInclusive},
+		{Collinear: CollinearInclusive, Clockwise: true},
+		{Prefilter: PrefilterOctagon},
+	}
+	for _, alg := range Algorithms[float64]() {
+		for _, opts := range modes {
+			for _, points := range [][][2]float64{nil, {}} {
+				hull, err := alg.Hull(context.Background(), points, opts)
+				if err != nil {
+					t.Fatalf("%s %+v: %v", alg.Name(), opts, err)
+				}
+				if hull == nil || len(hull) != 0 {
+					t.Fatalf("%s %+v: got %v, want an empty hull", alg.Name(), opts, hull)
+				}
+			}
+		}
+	}
+}
+
+// Stats reused across runs only report the latest one
+func TestStatsReset(t *testing.T) {
+	points := [][2]float64{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {1, 1}, {2, 2}, {3, 1}, {1, 3}}
+	var stats Stats
+	if _, err := SeqMonotoneChain(context.Background(), append([][2]float64(nil), points...), Options{Prefilter: PrefilterQuad, Stats: &stats}); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Eliminated == 0 {
+		t.Fatal("prefilter eliminated no interior points")
+	}
+	if _, err := SeqMonotoneChain(context.Background(), append([][2]float64(nil), points...), Options{Stats: &stats}); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Eliminated != 0 {
+		t.Fatalf("Eliminated = %d after a run without a prefilter, want 0", stats.Eliminated)
+	}
+}
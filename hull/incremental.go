@@ -0,0 +1,265 @@
+package hull
+
+/**********************
+ * Incremental hull   *
+ **********************
+Online hull for points arriving one at a time. The lower and upper chains of the hull (as in
+Andrew's monotone chain) are each kept in lexicographic order in a treap. A new point is either
+inside its chain's span (no change) or is inserted, after which its neighbors are removed while they
+no longer turn the right way. Every point is removed at most once, so an insert is O(log h) amortized.
+*/
+
+// Treap node holding one chain vertex
+type chain_node[T Coord] struct {
+	point       [2]T
+	priority    uint64
+	left, right *chain_node[T]
+}
+
+// One hull chain in lexicographic order. side is 1 for the lower chain (left turns from left to
+// right) and -1 for the upper chain.
+type chain[T Coord] struct {
+	root *chain_node[T]
+	size int
+	side int
+	// State of the generator for treap priorities
+	seed uint64
+}
+
+// Next treap priority (splitmix64)
+func (c *chain[T]) next_priority() uint64 {
+	c.seed += 0x9E3779B97F4A7C15
+	z := c.seed
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+	return z ^ z>>31
+}
+
+// Split n into the nodes before p and the nodes from p on
+func split_nodes[T Coord](n *chain_node[T], p [2]T) (*chain_node[T], *chain_node[T]) {
+	if n == nil {
+		return nil, nil
+	}
+	if lex_less(n.point, p) {
+		l, r := split_nodes(n.right, p)
+		n.right = l
+		return n, r
+	}
+	l, r := split_nodes(n.left, p)
+	n.left = r
+	return l, n
+}
+
+// Join treaps a and b, every node of a is before every node of b
+func merge_nodes[T Coord](a, b *chain_node[T]) *chain_node[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = merge_nodes(a.right, b)
+		return a
+	}
+	b.left = merge_nodes(a, b.left)
+	return b
+}
+
+func (c *chain[T]) insert(p [2]T) {
+	node := &chain_node[T]{point: p, priority: c.next_priority()}
+	// Descend until node belongs above the current subtree
+	link := &c.root
+	for *link != nil && (*link).priority > node.priority {
+		if lex_less(p, (*link).point) {
+			link = &(*link).left
+		} else {
+			link = &(*link).right
+		}
+	}
+	node.left, node.right = split_nodes(*link, p)
+	*link = node
+	c.size++
+}
+
+func (c *chain[T]) remove(p [2]T) {
+	link := &c.root
+	for *link != nil && (*link).point != p {
+		if lex_less(p, (*link).point) {
+			link = &(*link).left
+		} else {
+			link = &(*link).right
+		}
+	}
+	if *link != nil {
+		*link = merge_nodes((*link).left, (*link).right)
+		c.size--
+	}
+}
+
+// Whether p is a vertex of the chain
+func (c *chain[T]) has(p [2]T) bool {
+	n := c.root
+	for n != nil && n.point != p {
+		if lex_less(p, n.point) {
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return n != nil
+}
+
+// Last vertex before p, false if there is none
+func (c *chain[T]) before(p [2]T) ([2]T, bool) {
+	var res [2]T
+	found := false
+	for n := c.root; n != nil; {
+		if lex_less(n.point, p) {
+			res, found = n.point, true
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return res, found
+}
+
+// First vertex after p, false if there is none
+func (c *chain[T]) after(p [2]T) ([2]T, bool) {
+	var res [2]T
+	found := false
+	for n := c.root; n != nil; {
+		if lex_less(p, n.point) {
+			res, found = n.point, true
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return res, found
+}
+
+// Add p to the chain, returns whether the chain changed. Vertices removed to make room for p are
+// appended to removed unless it is nil.
+func (c *chain[T]) add(p [2]T, removed *[][2]T) bool {
+	if c.has(p) {
+		return false
+	}
+	pred, has_pred := c.before(p)
+	succ, has_succ := c.after(p)
+	if has_pred && has_succ && c.side*orient(pred, p, succ) <= 0 {
+		// On or inside the chain
+		return false
+	}
+	c.insert(p)
+
+	// Neighbors that no longer turn the right way are not on the chain
+	for has_pred {
+		pred2, ok := c.before(pred)
+		if !ok || c.side*orient(pred2, pred, p) > 0 {
+			break
+		}
+		c.remove(pred)
+		if removed != nil {
+			*removed = append(*removed, pred)
+		}
+		pred = pred2
+	}
+	for has_succ {
+		succ2, ok := c.after(succ)
+		if !ok || c.side*orient(p, succ, succ2) > 0 {
+			break
+		}
+		c.remove(succ)
+		if removed != nil {
+			*removed = append(*removed, succ)
+		}
+		succ = succ2
+	}
+	return true
+}
+
+// Undo adding p to the chain, given what add returned and the vertices it removed
+func (c *chain[T]) undo_add(p [2]T, inserted bool, removed [][2]T) {
+	if inserted {
+		c.remove(p)
+	}
+	for _, q := range removed {
+		c.insert(q)
+	}
+}
+
+// Vertices in lexicographic order
+func (c *chain[T]) points() [][2]T {
+	res := make([][2]T, 0, c.size)
+	var walk func(n *chain_node[T])
+	walk = func(n *chain_node[T]) {
+		if n != nil {
+			walk(n.left)
+			res = append(res, n.point)
+			walk(n.right)
+		}
+	}
+	walk(c.root)
+	return res
+}
+
+// Hull is the convex hull of a growing set of points. Points can be added one at a time or in
+// batches in O(log h) amortized time each, and the hull read at any time. A Hull is not safe for
+// concurrent use.
+type Hull[T Coord] struct {
+	lower, upper chain[T]
+}
+
+// NewHull returns an empty Hull
+func NewHull[T Coord]() *Hull[T] {
+	return &Hull[T]{
+		lower: chain[T]{side: 1, seed: 1},
+		upper: chain[T]{side: -1, seed: 2},
+	}
+}
+
+// Add adds p to the set, returns whether the hull changed
+func (h *Hull[T]) Add(p [2]T) bool {
+	lower := h.lower.add(p, nil)
+	upper := h.upper.add(p, nil)
+	return lower || upper
+}
+
+// AddPoints adds every point of points to the set, returns whether the hull changed
+func (h *Hull[T]) AddPoints(points [][2]T) bool {
+	changed := false
+	for _, p := range points {
+		if h.Add(p) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Len returns the number of hull vertices
+func (h *Hull[T]) Len() int {
+	if h.lower.size <= 1 {
+		return h.lower.size
+	}
+	return h.lower.size + h.upper.size - 2
+}
+
+// Vertices returns the current hull in canonical form (counterclockwise from the lowest, then
+// leftmost vertex, without collinear boundary points)
+func (h *Hull[T]) Vertices() [][2]T {
+	vertices := h.lower.points()
+	if len(vertices) <= 1 {
+		return vertices
+	}
+	// Upper chain right to left, its ends are already the ends of the lower chain
+	upper := h.upper.points()
+	for i := len(upper) - 2; i >= 1; i-- {
+		vertices = append(vertices, upper[i])
+	}
+
+	// Rotate to start from the lowest vertex
+	start := lowest_leftmost(vertices)
+	return append(vertices[start:len(vertices):len(vertices)], vertices[:start]...)
+}
@@ -0,0 +1,78 @@
+package hull
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Add points to a Hull one at a time and check it against monotone chain on the prefix after each
+func check_incremental[T Coord](t *testing.T, points [][2]T) {
+	t.Helper()
+	h := NewHull[T]()
+	prev := h.Vertices()
+	for i, p := range points {
+		changed := h.Add(p)
+		got := h.Vertices()
+		want, err := SeqMonotoneChain(context.Background(), slices.Clone(points[:i+1]), Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equal_hulls(got, want) {
+			t.Fatalf("add %d (%v): hull of %v\ngot  %v\nwant %v", i, p, points[:i+1], got, want)
+		}
+		if h.Len() != len(want) {
+			t.Fatalf("add %d: Len %d, want %d", i, h.Len(), len(want))
+		}
+		if changed != !equal_hulls(prev, got) {
+			t.Fatalf("add %d (%v): Add returned %v, hull went from %v to %v", i, p, changed, prev, got)
+		}
+		prev = got
+	}
+
+	// A batch gives the same hull
+	batch := NewHull[T]()
+	batch.AddPoints(points)
+	if !equal_hulls(batch.Vertices(), prev) {
+		t.Fatalf("AddPoints: got %v, want %v", batch.Vertices(), prev)
+	}
+}
+
+func TestHullUniform(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		check_incremental(t, random_points[float64](r, 300))
+	}
+}
+
+func TestHullGrid(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, side := range []int{1, 2, 3, 6} {
+		for trial := 0; trial < 20; trial++ {
+			points := make([][2]int32, 200)
+			for i := range points {
+				points[i] = [2]int32{int32(r.Intn(side)), int32(r.Intn(side))}
+			}
+			check_incremental(t, points)
+		}
+	}
+}
+
+func TestHullCollinearAndDuplicates(t *testing.T) {
+	tests := [][][2]int64{
+		// Along a line, outwards, inwards and repeated
+		{{0, 0}, {2, 2}, {1, 1}, {4, 4}, {4, 4}, {-1, -1}, {3, 3}},
+		// Vertical and horizontal lines, then off them
+		{{0, 0}, {0, 5}, {0, 2}, {0, -3}, {3, 0}, {-2, 0}, {0, 5}},
+		// Points on the edges of a square, then its corners again
+		{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {2, 0}, {4, 2}, {2, 4}, {0, 2}, {0, 0}, {4, 4}},
+		// Growing past a collinear run
+		{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {1, 1}, {4, 0}, {-1, 0}, {1, 1}, {1, -1}},
+		// A single point over and over
+		{{7, -7}, {7, -7}, {7, -7}},
+	}
+	for _, points := range tests {
+		check_incremental(t, points)
+	}
+}
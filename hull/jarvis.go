@@ -1,20 +1,22 @@
-package main
+package hull
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Find leftmost point relative to p
-func find_single_left(points [][2]float32, p int, left_map []int, wg *sync.WaitGroup) {
+func find_single_left[T Coord](points [][2]T, p int, left_map []int, wg *sync.WaitGroup) {
 	endpoint := 0
 	for candidate := range points {
-		cross := cross_prod(points[p], points[endpoint], points[candidate])
-		if endpoint == p || cross > 0 {
+		turn := orient(points[p], points[endpoint], points[candidate])
+		if points[endpoint] == points[p] || turn > 0 {
 			// New point is to the left of current endpoint
 			endpoint = candidate
-		} else if cross == 0 && dist(points[p], points[candidate]) >= dist(points[p], points[endpoint]) {
+		} else if turn == 0 && farther(points[p], points[candidate], points[endpoint]) {
 			// New point is collinear but further than current endpoint
 			endpoint = candidate
 		}
@@ -25,7 +27,7 @@ func find_single_left(points [][2]float32, p int, left_map []int, wg *sync.WaitG
 }
 
 // Naively precompute all leftmost points, O(N^2) no bueno
-func find_all_lefts(points [][2]float32) []int {
+func find_all_lefts[T Coord](points [][2]T) []int {
 	left_map := make([]int, len(points))
 	wg := sync.WaitGroup{}
 	for i := 0; i < len(points); i++ {
@@ -37,11 +39,11 @@ func find_all_lefts(points [][2]float32) []int {
 }
 
 // Parallel Jarvis March
-func naive_parallel_jarvis(points [][2]float32) [][2]float32 {
+func naive_parallel_jarvis[T Coord](points [][2]T) [][2]T {
 	if len(points) < 3 {
 		return points
 	}
-	var hull [][2]float32
+	var hull [][2]T
 	left := leftmost(points)
 	// Last selected point on hull
 	p := left
@@ -60,12 +62,13 @@ func naive_parallel_jarvis(points [][2]float32) [][2]float32 {
 }
 
 // Parallel Jarvis March - searches in parallel both left and right starting at multiple points on hull
-func parallel_jarvis(points [][2]float32) [][2]float32 {
+func parallel_jarvis[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
 	if len(points) < 3 {
-		return points
+		return points, nil
 	}
-	selected := make([]bool, len(points))
-	var hull [][2]float32
+	// Shared by all 8 wraps, accessed atomically (1 if on hull)
+	selected := make([]int32, len(points))
+	var hull [][2]T
 
 	find_extremes_start := time.Now()
 	left := leftmost(points)
@@ -76,58 +79,58 @@ func parallel_jarvis(points [][2]float32) [][2]float32 {
 
 	wg := sync.WaitGroup{}
 	do_left := func(start int) {
+		defer wg.Done()
 		left_p := start
-		for {
-			selected[left_p] = true
+		for !done(ctx) {
+			atomic.StoreInt32(&selected[left_p], 1)
 			// Find leftmost endpoint
 			endpoint := 0
 			for candidate := range points {
-				cross := cross_prod(points[left_p], points[endpoint], points[candidate])
-				if endpoint == left_p || cross < 0 {
+				if poll(ctx, candidate) {
+					return
+				}
+				turn := orient(points[left_p], points[endpoint], points[candidate])
+				if points[endpoint] == points[left_p] || turn < 0 {
 					// New point is to the left of current endpoint
 					endpoint = candidate
-				} else if cross == 0 && dist(points[left_p], points[candidate]) >= dist(points[left_p], points[endpoint]) {
-					// Really annoying edge case when jarvis won't converge b/c left is collinear with something "same distance"
-					if dist(points[left_p], points[candidate]) == dist(points[left_p], points[endpoint]) && endpoint != left {
-						// New point is collinear but further than current endpoint
-						endpoint = candidate
-					}
+				} else if turn == 0 && farther(points[left_p], points[candidate], points[endpoint]) {
+					// New point is collinear but further than current endpoint
+					endpoint = candidate
 				}
 			}
 			left_p = endpoint
 			// Circled back to original point
-			if selected[left_p] {
+			if atomic.LoadInt32(&selected[left_p]) == 1 {
 				break
 			}
 		}
-		wg.Done()
 	}
 	do_right := func(start int) {
+		defer wg.Done()
 		right_p := start
-		for {
-			selected[right_p] = true
+		for !done(ctx) {
+			atomic.StoreInt32(&selected[right_p], 1)
 			// Find leftmost endpoint
 			endpoint := 0
 			for candidate := range points {
-				cross := cross_prod(points[right_p], points[endpoint], points[candidate])
-				if endpoint == right_p || cross > 0 {
+				if poll(ctx, candidate) {
+					return
+				}
+				turn := orient(points[right_p], points[endpoint], points[candidate])
+				if points[endpoint] == points[right_p] || turn > 0 {
 					// New point is to the left of current endpoint
 					endpoint = candidate
-				} else if cross == 0 && dist(points[right_p], points[candidate]) >= dist(points[right_p], points[endpoint]) {
-					// Really annoying edge case when jarvis won't converge b/c left is collinear with something "same distance"
-					if dist(points[right_p], points[candidate]) == dist(points[right_p], points[endpoint]) && endpoint != left {
-						// New point is collinear but further than current endpoint
-						endpoint = candidate
-					}
+				} else if turn == 0 && farther(points[right_p], points[candidate], points[endpoint]) {
+					// New point is collinear but further than current endpoint
+					endpoint = candidate
 				}
 			}
 			right_p = endpoint
 			// Circled back to original point
-			if selected[right_p] {
+			if atomic.LoadInt32(&selected[right_p]) == 1 {
 				break
 			}
 		}
-		wg.Done()
 	}
 	wg.Add(8)
 	// Can go CCW or CCW from max X, max Y, min X, min Y
@@ -140,48 +143,54 @@ func parallel_jarvis(points [][2]float32) [][2]float32 {
 	go do_left(down)
 	go do_right(down)
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	for i := 0; i < len(points); i++ {
-		if selected[i] {
+		if selected[i] == 1 {
 			hull = append(hull, points[i])
 		}
 	}
 
-	return hull
+	return hull, nil
 }
 
 // Sequential Jarvis March
-func seq_jarvis(points [][2]float32) [][2]float32 {
+func seq_jarvis[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
 	if len(points) < 3 {
-		return points
+		return points, nil
 	}
-	var hull [][2]float32
+	var hull [][2]T
 	left := leftmost(points)
 	// Last selected point on hull
 	p := left
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		hull = append(hull, points[p])
 		// Find leftmost endpoint
 		endpoint := 0
 		for candidate := range points {
-			cross := cross_prod(points[p], points[endpoint], points[candidate])
-			if endpoint == p || cross < 0 {
+			if poll(ctx, candidate) {
+				return nil, ctx.Err()
+			}
+			turn := orient(points[p], points[endpoint], points[candidate])
+			if points[endpoint] == points[p] || turn < 0 {
 				// New point is to the left of current endpoint
 				endpoint = candidate
-			} else if cross == 0 && dist(points[p], points[candidate]) >= dist(points[p], points[endpoint]) {
-				// Really annoying edge case when jarvis won't converge b/c left is collinear with something "same distance"
-				if dist(points[p], points[candidate]) == dist(points[p], points[endpoint]) && endpoint != left {
-					// New point is collinear but further than current endpoint
-					endpoint = candidate
-				}
+			} else if turn == 0 && farther(points[p], points[candidate], points[endpoint]) {
+				// New point is collinear but further than current endpoint
+				endpoint = candidate
 			}
 		}
 		p = endpoint
-		// Circled back to original point
-		if endpoint == left {
+		// Circled back to original point (compare coordinates in case it is duplicated)
+		if points[endpoint] == points[left] {
 			break
 		}
 	}
 
-	return hull
+	return hull, nil
 }
@@ -0,0 +1,337 @@
+package hull
+
+import (
+	"cmp"
+	"context"
+	"sync"
+)
+
+/******************************
+ * Kirkpatrick–Seidel         *
+ ******************************
+"The ultimate planar convex hull algorithm?" - marriage before conquest. The upper and lower hull
+chains are found separately: split the points at the median x, find the hull edge (bridge) that
+crosses the split in linear time by prune and search on pair slopes, then recurse on the points
+left and right of the bridge that can still be on the hull. Every level is linear and there are
+at most log h levels with hull vertices, so the total is O(n log h).
+
+Both chains use the same code with side = 1 for the upper and -1 for the lower hull, which flips
+every orientation and y comparison (reflecting the points instead would overflow integer types).
+*/
+
+// Subproblems smaller than this are not split across goroutines
+const PAR_KS_LIMIT int = 2000
+
+// Rearrange a so a[k] is the element that would be there if a were sorted, with no greater
+// elements before it and no smaller ones after. Worst case linear time (median of medians). Stops
+// early, leaving a partly arranged, if ctx is cancelled.
+func select_kth[E any](ctx context.Context, a []E, k int, less func(E, E) bool) {
+	for len(a) > 5 {
+		// Pivot is the median of the medians of groups of 5, moved to the front of a
+		n_groups := (len(a) + 4) / 5
+		for g := 0; g < n_groups; g++ {
+			group := a[5*g : min(5*g+5, len(a))]
+			insertion_sort(group, less)
+			a[g], group[len(group)/2] = group[len(group)/2], a[g]
+		}
+		select_kth(ctx, a[:n_groups], n_groups/2, less)
+		pivot := a[n_groups/2]
+
+		// Three way partition so runs of equal elements always make progress
+		lt, gt := partition3(ctx, a, pivot, less)
+		if k < lt {
+			a = a[:lt]
+		} else if k >= gt {
+			a = a[gt:]
+			k -= gt
+		} else {
+			return
+		}
+	}
+	insertion_sort(a, less)
+}
+
+// Partition a into elements less than, equal to and greater than pivot, returns the bounds of the equal part.
+// If ctx is cancelled part way the elements not yet looked at are left between the bounds.
+func partition3[E any](ctx context.Context, a []E, pivot E, less func(E, E) bool) (int, int) {
+	lt, i, gt := 0, 0, len(a)
+	for steps := 0; i < gt; steps++ {
+		if poll(ctx, steps) {
+			break
+		}
+		if less(a[i], pivot) {
+			a[lt], a[i] = a[i], a[lt]
+			lt++
+			i++
+		} else if less(pivot, a[i]) {
+			gt--
+			a[gt], a[i] = a[i], a[gt]
+		} else {
+			i++
+		}
+	}
+	return lt, gt
+}
+
+// Insertion sort for small slices
+func insertion_sort[E any](a []E, less func(E, E) bool) {
+	for i := 1; i < len(a); i++ {
+		for j := i; j > 0 && less(a[j], a[j-1]); j-- {
+			a[j], a[j-1] = a[j-1], a[j]
+		}
+	}
+}
+
+// Pair of candidate points ordered by x, with an approximate slope (scaled by side)
+type slope_pair[T Coord] struct {
+	p, q  [2]T
+	slope float64
+}
+
+// Find the hull edge on side crossing the split between points with is_left true and false.
+// candidates must have points on both sides of the split and is reordered. Returns the edge
+// left to right, or zero points if ctx is cancelled.
+func bridge[T Coord](ctx context.Context, candidates [][2]T, is_left func([2]T) bool, side int) ([2]T, [2]T) {
+	// Slope of pair a is less than slope of pair b, exact
+	slope_less := func(a, b slope_pair[T]) bool {
+		return side*cross_sign(a.p, a.q, b.p, b.q) > 0
+	}
+
+	for {
+		if done(ctx) {
+			return [2]T{}, [2]T{}
+		}
+		if len(candidates) == 2 {
+			p, q := candidates[0], candidates[1]
+			if q[0] < p[0] {
+				p, q = q, p
+			}
+			return p, q
+		}
+
+		// Pair up candidates, of a pair with the same x only the one further out on side can be on the hull
+		next := make([][2]T, 0, len(candidates))
+		pairs := make([]slope_pair[T], 0, len(candidates)/2)
+		if len(candidates)%2 == 1 {
+			next = append(next, candidates[len(candidates)-1])
+		}
+		for i := 0; i+1 < len(candidates); i += 2 {
+			p, q := candidates[i], candidates[i+1]
+			if q[0] < p[0] {
+				p, q = q, p
+			}
+			if p[0] == q[0] {
+				if side*cmp.Compare(p[1], q[1]) > 0 {
+					next = append(next, p)
+				} else {
+					next = append(next, q)
+				}
+				continue
+			}
+			dx := float64(q[0])/2 - float64(p[0])/2
+			dy := float64(q[1])/2 - float64(p[1])/2
+			pairs = append(pairs, slope_pair[T]{p, q, float64(side) * dy / dx})
+		}
+		if len(pairs) == 0 {
+			candidates = next
+			continue
+		}
+
+		// Supporting line with the median slope, pk and pm are the leftmost and rightmost points on it.
+		// Pruning only needs a slope near the median, so the median is selected by the float
+		// approximations, which is much cheaper than exact comparisons. Halving keeps them finite.
+		slopes := make([]float64, len(pairs))
+		for i, pair := range pairs {
+			slopes[i] = pair.slope
+		}
+		select_kth(ctx, slopes, len(slopes)/2, func(a, b float64) bool { return a < b })
+		median := pairs[0]
+		for _, pair := range pairs {
+			if pair.slope == slopes[len(slopes)/2] {
+				median = pair
+				break
+			}
+		}
+		pk, pm := candidates[0], candidates[0]
+		for i, p := range candidates[1:] {
+			if poll(ctx, i) {
+				return [2]T{}, [2]T{}
+			}
+			height := side * cross_sign(median.p, median.q, pk, p)
+			if height > 0 {
+				pk, pm = p, p
+			} else if height == 0 {
+				if p[0] < pk[0] {
+					pk = p
+				}
+				if p[0] > pm[0] {
+					pm = p
+				}
+			}
+		}
+		if is_left(pk) && !is_left(pm) {
+			return pk, pm
+		}
+
+		// The bridge is less steep than the median (line touches left of the split) or steeper,
+		// either way one point of each pair on the wrong side of the median slope can't be on it
+		touches_left := is_left(pm)
+		for _, pair := range pairs {
+			if touches_left && !slope_less(pair, median) {
+				next = append(next, pair.q)
+			} else if !touches_left && !slope_less(median, pair) {
+				next = append(next, pair.p)
+			} else {
+				next = append(next, pair.p, pair.q)
+			}
+		}
+		candidates = next
+	}
+}
+
+// Hull chain on side of the line left->right (side 1 for the upper hull, -1 for the lower), from
+// left to right including both. points are the points strictly on side of the line. Returns nil
+// if ctx is cancelled.
+func ks_chain[T Coord](ctx context.Context, points [][2]T, left, right [2]T, side int, parallel bool) [][2]T {
+	if done(ctx) {
+		return nil
+	}
+	if len(points) == 0 {
+		return [][2]T{left, right}
+	}
+
+	// Split at the median x, keeping right on the right so both sides are non-empty
+	candidates := make([][2]T, 0, len(points)+2)
+	candidates = append(append(candidates, points...), left, right)
+	xs := make([]T, len(candidates))
+	for i, p := range candidates {
+		xs[i] = p[0]
+	}
+	select_kth(ctx, xs, len(xs)/2, func(a, b T) bool { return a < b })
+	split := xs[len(xs)/2]
+	is_left := func(p [2]T) bool { return p[0] <= split }
+	if split == right[0] {
+		is_left = func(p [2]T) bool { return p[0] < split }
+	}
+
+	bridge_left, bridge_right := bridge(ctx, candidates, is_left, side)
+	if done(ctx) {
+		return nil
+	}
+
+	// Only points outside the lines from the ends to the bridge can still be on the chain
+	var left_points, right_points [][2]T
+	for i, p := range points {
+		if poll(ctx, i) {
+			return nil
+		}
+		if orient(left, bridge_left, p) == side {
+			left_points = append(left_points, p)
+		} else if orient(bridge_right, right, p) == side {
+			right_points = append(right_points, p)
+		}
+	}
+
+	left_chain := [][2]T{left}
+	right_chain := [][2]T{right}
+	sub_chain := func(chain *[][2]T, points [][2]T, from, to [2]T) {
+		if from != to {
+			*chain = ks_chain(ctx, points, from, to, side, parallel)
+		}
+	}
+	if parallel && len(points) > PAR_KS_LIMIT {
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			sub_chain(&left_chain, left_points, left, bridge_left)
+		}()
+		go func() {
+			defer wg.Done()
+			sub_chain(&right_chain, right_points, bridge_right, right)
+		}()
+		wg.Wait()
+	} else {
+		sub_chain(&left_chain, left_points, left, bridge_left)
+		sub_chain(&right_chain, right_points, bridge_right, right)
+	}
+	if left_chain == nil || right_chain == nil {
+		return nil
+	}
+	return append(left_chain, right_chain...)
+}
+
+// Kirkpatrick–Seidel, hull is returned counterclockwise
+func kirkpatrick_seidel[T Coord](ctx context.Context, points [][2]T, parallel bool) ([][2]T, error) {
+	if len(points) < 3 {
+		return points, nil
+	}
+
+	// Ends of the chains: lowest and highest of the leftmost and of the rightmost points
+	left_lo, left_hi, right_lo, right_hi := points[0], points[0], points[0], points[0]
+	for _, p := range points {
+		if p[0] < left_lo[0] || (p[0] == left_lo[0] && p[1] < left_lo[1]) {
+			left_lo = p
+		}
+		if p[0] < left_hi[0] || (p[0] == left_hi[0] && p[1] > left_hi[1]) {
+			left_hi = p
+		}
+		if p[0] > right_lo[0] || (p[0] == right_lo[0] && p[1] < right_lo[1]) {
+			right_lo = p
+		}
+		if p[0] > right_hi[0] || (p[0] == right_hi[0] && p[1] > right_hi[1]) {
+			right_hi = p
+		}
+	}
+	if left_lo[0] == right_lo[0] {
+		// All points on a vertical line
+		return [][2]T{left_lo, left_hi}, nil
+	}
+
+	var upper_points, lower_points [][2]T
+	for i, p := range points {
+		if poll(ctx, i) {
+			return nil, ctx.Err()
+		}
+		if orient(left_hi, right_hi, p) > 0 {
+			upper_points = append(upper_points, p)
+		} else if orient(left_lo, right_lo, p) < 0 {
+			lower_points = append(lower_points, p)
+		}
+	}
+
+	var upper, lower [][2]T
+	if parallel {
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			upper = ks_chain(ctx, upper_points, left_hi, right_hi, 1, parallel)
+		}()
+		go func() {
+			defer wg.Done()
+			lower = ks_chain(ctx, lower_points, left_lo, right_lo, -1, parallel)
+		}()
+		wg.Wait()
+	} else {
+		upper = ks_chain(ctx, upper_points, left_hi, right_hi, 1, parallel)
+		lower = ks_chain(ctx, lower_points, left_lo, right_lo, -1, parallel)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Lower chain left to right then upper chain back, ends of the chains may be repeated
+	reverse(upper)
+	return append(lower, upper...), nil
+}
+
+// Run Kirkpatrick–Seidel serially
+func seq_kirkpatrick_seidel[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+	return kirkpatrick_seidel(ctx, points, false)
+}
+
+// Run Kirkpatrick–Seidel with recursive calls in new goroutines
+func parallel_kirkpatrick_seidel[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+	return kirkpatrick_seidel(ctx, points, true)
+}
@@ -0,0 +1,118 @@
+package hull
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+/**********************
+ * Point location     *
+ **********************
+Classifies query points against a hull that is computed once. The hull is reduced to its strictly
+convex vertices counterclockwise, and each query binary searches the fan of triangles around the
+first vertex for the wedge holding the point (locate in boundary.go), then tests it against that
+wedge's hull edge. O(log h) per query, the batch API splits the queries into chunks located in
+parallel.
+*/
+
+// Location of a point relative to a hull
+type Location int
+
+const (
+	Outside  Location = outside
+	Boundary Location = boundary
+	Inside   Location = inside
+)
+
+func (l Location) String() string {
+	switch l {
+	case Outside:
+		return "outside"
+	case Boundary:
+		return "boundary"
+	case Inside:
+		return "inside"
+	}
+	return fmt.Sprintf("Location(%d)", int(l))
+}
+
+// Smallest number of queries worth handing to another goroutine
+const PAR_LOCATE_CHUNK int = 4096
+
+// Locator answers point-in-hull queries against a fixed convex polygon. It is safe for concurrent
+// use.
+type Locator[T Coord] struct {
+	// Strictly convex vertices counterclockwise, or the 1 or 2 extreme points of a polygon with
+	// no area
+	hull [][2]T
+	// Index of each vertex in the polygon it was built from
+	index []int
+}
+
+// NewLocator preprocesses a convex polygon given in order, clockwise or counterclockwise, such as
+// the output of any hull algorithm, for point location. Collinear boundary points and repeated
+// vertices are allowed. A polygon with no area is a segment (or a point), and queries on it are
+// either on the boundary or outside. O(h)
+func NewLocator[T Coord](polygon [][2]T) (*Locator[T], error) {
+	if len(polygon) == 0 {
+		return nil, ErrEmptyPolygon
+	}
+	vertices, err := convex_vertices(polygon)
+	if err != nil {
+		return nil, err
+	}
+	hull := make([][2]T, len(vertices))
+	for i, v := range vertices {
+		hull[i] = polygon[v]
+	}
+	return &Locator[T]{hull: hull, index: vertices}, nil
+}
+
+// Locate classifies p as inside, on the boundary of or outside the hull, exactly. O(log h)
+func (l *Locator[T]) Locate(p [2]T) Location {
+	switch len(l.hull) {
+	case 1:
+		if p == l.hull[0] {
+			return Boundary
+		}
+		return Outside
+	case 2:
+		a, b := l.hull[0], l.hull[1]
+		if orient(a, b, p) == 0 && along(a, b, a, p) >= 0 && along(a, b, p, b) >= 0 {
+			return Boundary
+		}
+		return Outside
+	}
+	where, _ := locate(l.hull, p)
+	return Location(where)
+}
+
+// LocateAll classifies every query point, in parallel for large batches. The result is in the
+// order of queries.
+func (l *Locator[T]) LocateAll(ctx context.Context, queries [][2]T) ([]Location, error) {
+	res := make([]Location, len(queries))
+	workers := runtime.GOMAXPROCS(0)
+	chunk_size := max((len(queries)+workers-1)/workers, PAR_LOCATE_CHUNK)
+
+	wg := sync.WaitGroup{}
+	for start := 0; start < len(queries); start += chunk_size {
+		end := min(start+chunk_size, len(queries))
+		wg.Add(1)
+		go func(chunk [][2]T, out []Location) {
+			defer wg.Done()
+			for i, p := range chunk {
+				if poll(ctx, i) {
+					return
+				}
+				out[i] = l.Locate(p)
+			}
+		}(queries[start:end], res[start:end])
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
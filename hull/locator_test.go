@@ -0,0 +1,197 @@
+package hull
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+// Random points on a size x size grid, so that queries on the same grid hit edges and vertices
+func grid_points(r *rand.Rand, n int, size int) [][2]int64 {
+	points := make([][2]int64, n)
+	for i := range points {
+		points[i] = [2]int64{int64(r.Intn(size)), int64(r.Intn(size))}
+	}
+	return points
+}
+
+// Strictly convex hull of points counterclockwise, and the same hull passed to a locator in the
+// other ways it may come: clockwise and with collinear boundary points
+func hull_variants(t *testing.T, points [][2]int64) (strict [][2]int64, variants [][][2]int64) {
+	t.Helper()
+	for _, opts := range []Options{{}, {Clockwise: true}, {Collinear: CollinearInclusive}, {Clockwise: true, Collinear: CollinearInclusive}} {
+		h, err := SeqMonotoneChain(context.Background(), append([][2]int64{}, points...), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		variants = append(variants, h)
+	}
+	return variants[0], variants
+}
+
+// Location of p against a strictly convex counterclockwise hull by testing every edge
+func brute_force_locate[T Coord](hull [][2]T, p [2]T) Location {
+	switch len(hull) {
+	case 1:
+		if p == hull[0] {
+			return Boundary
+		}
+		return Outside
+	case 2:
+		a, b := hull[0], hull[1]
+		if orient(a, b, p) == 0 && min(a[0], b[0]) <= p[0] && p[0] <= max(a[0], b[0]) && min(a[1], b[1]) <= p[1] && p[1] <= max(a[1], b[1]) {
+			return Boundary
+		}
+		return Outside
+	}
+	res := Inside
+	for i, a := range hull {
+		switch orient(a, hull[(i+1)%len(hull)], p) {
+		case -1:
+			return Outside
+		case 0:
+			res = Boundary
+		}
+	}
+	return res
+}
+
+// Query points around a hull on the grid its points come from: every grid point near it, its
+// vertices and points on the extensions of its edges
+func grid_queries(hull [][2]int64, size int) [][2]int64 {
+	var queries [][2]int64
+	for x := -2; x < size+2; x++ {
+		for y := -2; y < size+2; y++ {
+			queries = append(queries, [2]int64{int64(x), int64(y)})
+		}
+	}
+	for i, a := range hull {
+		b := hull[(i+1)%len(hull)]
+		queries = append(queries, a, [2]int64{2*b[0] - a[0], 2*b[1] - a[1]}, [2]int64{2*a[0] - b[0], 2*a[1] - b[1]})
+	}
+	return queries
+}
+
+func TestLocatorBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	counts := map[Location]int{}
+	for trial := 0; trial < 300; trial++ {
+		size := 2 + r.Intn(10)
+		strict, variants := hull_variants(t, grid_points(r, 1+r.Intn(20), size))
+		queries := grid_queries(strict, size)
+		for _, polygon := range variants {
+			l, err := NewLocator(polygon)
+			if err != nil {
+				t.Fatalf("locator of %v: %v", polygon, err)
+			}
+			for _, q := range queries {
+				got, want := l.Locate(q), brute_force_locate(strict, q)
+				if got != want {
+					t.Fatalf("%v in %v: got %v, want %v", q, polygon, got, want)
+				}
+				counts[got]++
+			}
+		}
+	}
+	// Sanity check that every kind of query came up
+	if counts[Inside] == 0 || counts[Boundary] == 0 || counts[Outside] == 0 {
+		t.Fatalf("query locations %v", counts)
+	}
+
+	for trial := 0; trial < 100; trial++ {
+		points := make([][2]float64, 3+r.Intn(50))
+		for i := range points {
+			points[i] = [2]float64{r.NormFloat64(), r.NormFloat64()}
+		}
+		polygon, err := SeqMonotoneChain(context.Background(), points, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		l, err := NewLocator(polygon)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			q := [2]float64{2 * r.NormFloat64(), 2 * r.NormFloat64()}
+			if got, want := l.Locate(q), brute_force_locate(polygon, q); got != want {
+				t.Fatalf("%v in %v: got %v, want %v", q, polygon, got, want)
+			}
+		}
+	}
+}
+
+func TestLocatorDegenerate(t *testing.T) {
+	tests := []struct {
+		name    string
+		polygon [][2]int64
+		queries map[[2]int64]Location
+	}{
+		{"point", [][2]int64{{1, 1}, {1, 1}}, map[[2]int64]Location{{1, 1}: Boundary, {1, 2}: Outside, {0, 0}: Outside}},
+		{"segment", [][2]int64{{0, 0}, {4, 2}}, map[[2]int64]Location{
+			{0, 0}: Boundary, {2, 1}: Boundary, {4, 2}: Boundary, {6, 3}: Outside, {-2, -1}: Outside, {2, 2}: Outside,
+		}},
+		{"collinear", [][2]int64{{0, 0}, {1, 1}, {2, 2}, {1, 1}}, map[[2]int64]Location{
+			{0, 0}: Boundary, {1, 1}: Boundary, {2, 2}: Boundary, {3, 3}: Outside, {1, 0}: Outside,
+		}},
+	}
+	for _, test := range tests {
+		l, err := NewLocator(test.polygon)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for q, want := range test.queries {
+			if got := l.Locate(q); got != want {
+				t.Fatalf("%s: %v is %v, want %v", test.name, q, got, want)
+			}
+		}
+	}
+
+	if _, err := NewLocator([][2]int64{}); !errors.Is(err, ErrEmptyPolygon) {
+		t.Fatalf("empty polygon: got %v, want %v", err, ErrEmptyPolygon)
+	}
+	if _, err := NewLocator([][2]int64{{0, 0}, {2, 0}, {1, 1}, {2, 2}, {0, 2}}); !errors.Is(err, ErrNotConvex) {
+		t.Fatalf("reflex vertex: got %v, want %v", err, ErrNotConvex)
+	}
+}
+
+func TestLocateAll(t *testing.T) {
+	// Several workers even on a single CPU, so the batch is split into chunks
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	r := rand.New(rand.NewSource(2))
+	polygon, err := SeqMonotoneChain(context.Background(), grid_points(r, 100, 1000), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := NewLocator(polygon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{0, 1, PAR_LOCATE_CHUNK - 1, 5*PAR_LOCATE_CHUNK + 17} {
+		queries := grid_points(r, n, 1000)
+		// Some on the boundary
+		for i := 0; i < n; i += 7 {
+			queries[i] = polygon[i%len(polygon)]
+		}
+		got, err := l.LocateAll(context.Background(), queries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != n {
+			t.Fatalf("%d queries: got %d locations", n, len(got))
+		}
+		for i, q := range queries {
+			if want := l.Locate(q); got[i] != want {
+				t.Fatalf("%d queries: query %d %v is %v, want %v", n, i, q, got[i], want)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.LocateAll(ctx, grid_points(r, 5*PAR_LOCATE_CHUNK, 1000)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled: got %v, want %v", err, context.Canceled)
+	}
+}
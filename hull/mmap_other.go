@@ -0,0 +1,17 @@
+//go:build !unix
+
+package hull
+
+import (
+	"io"
+	"os"
+)
+
+// No memory mapping on this platform, read the whole file instead
+func map_file(file *os.File, size int) ([]byte, func() error, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, nil, err
+	}
+	return data, nil, nil
+}
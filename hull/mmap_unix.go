@@ -0,0 +1,18 @@
+//go:build unix
+
+package hull
+
+import (
+	"os"
+	"syscall"
+)
+
+// Map size bytes of file privately, so writes to the points (e.g. Graham scan sorting in place)
+// are copy-on-write and never reach the file
+func map_file(file *os.File, size int) ([]byte, func() error, error) {
+	data, err := syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,91 @@
+package hull
+
+import (
+	"context"
+	"sync"
+)
+
+/***************************
+ * Andrew's monotone chain *
+ ***************************
+Sorts the points lexicographically (by x, then y) and builds the lower and upper hulls in one
+pass each. Unlike Graham scan there is no angular comparator, so collinear points and ties need
+no distance tie-breaks - duplicates are the only points that compare equal.
+*/
+
+// Lexicographic order on points, by x then y
+func lex_less[T Coord](a, b [2]T) bool {
+	return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+}
+
+// Monotone chain, returns the hull counterclockwise or nil if ctx is cancelled. points is sorted in place.
+func monotone_chain_run[T Coord](ctx context.Context, points [][2]T, parallel_sort bool) [][2]T {
+	if parallel_sort {
+		wg := new(sync.WaitGroup)
+		wg.Add(1)
+		parallel_qsort(ctx, points, lex_less[T], wg)
+		wg.Wait()
+	} else {
+		qsort(ctx, points, lex_less[T])
+	}
+	if done(ctx) {
+		// Sort may have stopped early
+		return nil
+	}
+	return sorted_monotone_chain(ctx, points)
+}
+
+// Monotone chain on points already in lexicographic order, returns the hull counterclockwise or nil if ctx is cancelled
+func sorted_monotone_chain[T Coord](ctx context.Context, points [][2]T) [][2]T {
+	hull := make([][2]T, 0, len(points)/4)
+	// Lower hull, left to right. Pop while the last two points and p don't make a left turn.
+	for i, p := range points {
+		if poll(ctx, i) {
+			return nil
+		}
+		for len(hull) >= 2 && orient(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// Upper hull, right to left, never popping into the lower hull
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		if poll(ctx, i) {
+			return nil
+		}
+		p := points[i]
+		for len(hull) >= lower && orient(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// Last point is the leftmost point again
+	return hull[:len(hull)-1]
+}
+
+// Run monotone chain with a serial sort
+func seq_monotone_chain[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+	if len(points) < 3 {
+		return points, nil
+	}
+	hull := monotone_chain_run(ctx, points, false)
+	if hull == nil {
+		return nil, ctx.Err()
+	}
+	return hull, nil
+}
+
+// Run monotone chain with parallel quicksort
+func parallel_monotone_chain[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+	if len(points) < 3 {
+		return points, nil
+	}
+	hull := monotone_chain_run(ctx, points, true)
+	if hull == nil {
+		return nil, ctx.Err()
+	}
+	return hull, nil
+}
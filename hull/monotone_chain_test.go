@@ -0,0 +1,119 @@
+package hull
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Points of a w by h grid in lexicographic order
+func sorted_grid(w, h int64) [][2]int64 {
+	grid := make([][2]int64, 0, w*h)
+	for x := int64(0); x < w; x++ {
+		for y := int64(0); y < h; y++ {
+			grid = append(grid, [2]int64{x, y})
+		}
+	}
+	return grid
+}
+
+// Run alg on sorted, reverse sorted and all-equal grids, which used to make the quicksort quadratic
+func check_sorted_grid(t *testing.T, alg func(context.Context, [][2]int64, Options) ([][2]int64, error)) {
+	t.Helper()
+	const side = 300
+	grid := sorted_grid(side, side)
+	reversed := slices.Clone(grid)
+	slices.Reverse(reversed)
+	same := make([][2]int64, side*side)
+	for i := range same {
+		same[i] = [2]int64{7, 7}
+	}
+	corners := [][2]int64{{0, 0}, {side - 1, 0}, {side - 1, side - 1}, {0, side - 1}}
+
+	inputs := []struct {
+		name   string
+		points [][2]int64
+		want   [][2]int64
+	}{
+		{"sorted", grid, corners},
+		{"reversed", reversed, corners},
+		{"duplicates", same, [][2]int64{{7, 7}}},
+	}
+	for _, in := range inputs {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		hull, err := alg(ctx, slices.Clone(in.points), Options{})
+		cancel()
+		if err != nil {
+			t.Fatalf("%s: %v", in.name, err)
+		}
+		if !equal_hulls(hull, in.want) {
+			t.Fatalf("%s: got %v, want %v", in.name, hull, in.want)
+		}
+	}
+}
+
+func TestMonotoneChainSortedGrid(t *testing.T) {
+	check_sorted_grid(t, SeqMonotoneChain[int64])
+	check_sorted_grid(t, ParallelMonotoneChain[int64])
+}
+
+// Cancelling part way through a sort stops it within a few polls
+func TestQsortCancel(t *testing.T) {
+	const cancel_after = 100000
+	for _, parallel := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		var compared atomic.Int64
+		cmp := func(a, b [2]int64) bool {
+			if compared.Add(1) == cancel_after {
+				cancel()
+			}
+			return lex_less(a, b)
+		}
+		points := sorted_grid(2048, 512)
+		if parallel {
+			wg := new(sync.WaitGroup)
+			wg.Add(1)
+			parallel_qsort(ctx, points, cmp, wg)
+			wg.Wait()
+		} else {
+			qsort(ctx, points, cmp)
+		}
+		cancel()
+		// Every goroutine still partitioning finishes at most one poll interval (2 comparisons a step)
+		if extra := compared.Load() - cancel_after; extra > 64*poll_interval {
+			t.Fatalf("parallel=%v: %d comparisons after cancelling", parallel, extra)
+		}
+	}
+}
+
+// A deadline during the sort of a large sorted input returns well before the sort would finish
+func check_deadline(t *testing.T, alg func(context.Context, [][2]int64, Options) ([][2]int64, error)) {
+	t.Helper()
+	points := sorted_grid(1024, 1024)
+	start := time.Now()
+	if _, err := alg(context.Background(), slices.Clone(points), Options{}); err != nil {
+		t.Fatal(err)
+	}
+	full := time.Since(start)
+
+	ctx, cancel := context.WithTimeout(context.Background(), full/20)
+	start = time.Now()
+	_, err := alg(ctx, slices.Clone(points), Options{})
+	elapsed := time.Since(start)
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed > full/2 {
+		t.Fatalf("cancelled after %v, a full run takes %v", elapsed, full)
+	}
+}
+
+func TestMonotoneChainDeadline(t *testing.T) {
+	check_deadline(t, SeqMonotoneChain[int64])
+	check_deadline(t, ParallelMonotoneChain[int64])
+}
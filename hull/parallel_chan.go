@@ -1,6 +1,7 @@
-package main
+package hull
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -8,22 +9,24 @@ import (
 )
 
 // Parallel subhull computation that uses a thread-pool like thing to limit the number of goroutines to MAX_SUBHULL_WORKERS
-func thread_pool_subhull(points [][2]float32, group_size, n int, subhulls [][2]float32, subhull_sizes []int) ([][2]float32, []int) {
+// Stops handing out work once ctx is cancelled, so the result is incomplete if ctx.Err() != nil
+func thread_pool_subhull[T Coord](ctx context.Context, points [][2]T, group_size, n int, subhulls [][2]T, subhull_sizes []int) ([][2]T, []int) {
 	const MAX_SUBHULL_WORKERS int = 300
 
 	var subhull_compute time.Duration
 	var subhull_append time.Duration
 
 	// Sync primitives to manage worker/manager communication + lifecycle
-	work_ch := make(chan [][2]float32)
-	result_ch := make(chan [][2]float32)
+	work_ch := make(chan [][2]T)
+	result_ch := make(chan [][2]T)
 	wg := sync.WaitGroup{}
 
 	// Worker function to process a subhull and send back to manager
 	subhull_worker := func() {
 		defer wg.Done()
 		for points := range work_ch {
-			result_ch <- parallel_graham_scan(points)
+			subhull, _ := parallel_graham_scan(ctx, points)
+			result_ch <- subhull
 		}
 	}
 
@@ -43,10 +46,9 @@ func thread_pool_subhull(points [][2]float32, group_size, n int, subhulls [][2]f
 	manager_wg.Add(1)
 	manager := func() {
 		defer manager_wg.Done()
-		// Central manager to collect subhulls from workers
-		for i := 0; i < num_workers; i++ {
-			subhull_start := time.Now()
-			subhull := <-result_ch
+		// Central manager to collect subhulls from workers (there can be more subhulls than workers)
+		subhull_start := time.Now()
+		for subhull := range result_ch {
 			subhull_compute += time.Since(subhull_start)
 
 			append_start := time.Now()
@@ -55,6 +57,7 @@ func thread_pool_subhull(points [][2]float32, group_size, n int, subhulls [][2]f
 			// but track sizes to know offset/index in later
 			subhull_sizes = append(subhull_sizes, len(subhull))
 			subhull_append += time.Since(append_start)
+			subhull_start = time.Now()
 		}
 	}
 
@@ -64,18 +67,24 @@ func thread_pool_subhull(points [][2]float32, group_size, n int, subhulls [][2]f
 
 	// Run graham scan on subgroups of points
 	// Send groups of points to worker goroutines
+send:
 	for start := 0; start < n; start += group_size {
 		end := start + group_size
 		if n < end {
 			end = n
 		}
-		work_ch <- points[start:end]
+		select {
+		case work_ch <- points[start:end]:
+		case <-ctx.Done():
+			break send
+		}
 	}
 
 	// Finished sending work
 	close(work_ch)
-	// Wait for workers to complete
+	// Wait for workers to complete, then let the manager finish
 	wg.Wait()
+	close(result_ch)
 	manager_wg.Wait()
 
 	// Report compute metrics
@@ -86,14 +95,15 @@ func thread_pool_subhull(points [][2]float32, group_size, n int, subhulls [][2]f
 }
 
 // Parallel subhull computation that spawns 1 goroutine for each subhull
-func basic_par_subhull(points [][2]float32, group_size, n int, subhulls [][2]float32, subhull_sizes []int) ([][2]float32, []int) {
+func basic_par_subhull[T Coord](ctx context.Context, points [][2]T, group_size, n int, subhulls [][2]T, subhull_sizes []int) ([][2]T, []int) {
 	var subhull_compute time.Duration
 	var subhull_append time.Duration
 
 	num_subhulls := 0
-	ch := make(chan [][2]float32)
-	worker := func(points [][2]float32, ch chan [][2]float32) {
-		ch <- parallel_graham_scan(points)
+	ch := make(chan [][2]T)
+	worker := func(points [][2]T, ch chan [][2]T) {
+		subhull, _ := parallel_graham_scan(ctx, points)
+		ch <- subhull
 	}
 	// Run graham scan on subgroups of points
 	for start := 0; start < n; start += group_size {
@@ -131,32 +141,37 @@ func basic_par_subhull(points [][2]float32, group_size, n int, subhulls [][2]flo
 // 	Tracks global state w atomic counter and concurrent hash map
 //  Note that subhull_points is points on subhulls concatenated together, can be split with subhull_sizes
 //  i.e. subhull_points = [subhull1_point1, subhull1_point2, ..., subhull2_point1, ...  subhullN_pointK]
-func parallel_subhull_jarvis(subhull_points [][2]float32, subhull_sizes []int, group_size int) [][2]float32 {
-	var hull [][2]float32
+func parallel_subhull_jarvis[T Coord](ctx context.Context, subhull_points [][2]T, subhull_sizes []int, group_size int) [][2]T {
+	var hull [][2]T
 	n_subhulls := len(subhull_sizes)
 
 	// Use a concurrent hashmap to track which points have been selected (used as a set here)
-	selected := SafeMap{}
-	selected.init(len(subhull_points) / 4)
+	selected := SafeMap[[2]T, bool]{}
+	selected.Init(len(subhull_points)/4, hash_point[T])
 
 	// Leftmost point starts as first point on hull
 	left := leftmost(subhull_points)
 	right := rightmost(subhull_points)
 
 	wg := sync.WaitGroup{}
-	failed := false
+	// Set by either worker if the wrap needs more than group_size steps (or ctx is cancelled)
+	var failed atomic.Bool
 
 	// Track total steps taken by all workers with atomic counter
 	var steps uint64
 
 	// Worker to wrap subhulls from start point
-	find_hull := func(start int, order float32) {
+	find_hull := func(start int, order int) {
 		defer wg.Done()
-		tangents := make([][2]float32, n_subhulls)
+		tangents := make([][2]T, n_subhulls)
 		cur_p := subhull_points[start]
 		// If need to add more points than the group size, retry with different group size
 		for {
-			selected.put(cur_p, [][2]float32{[2]float32{1.0, 1.0}})
+			if done(ctx) {
+				failed.Store(true)
+				break
+			}
+			selected.Put(cur_p, []bool{true})
 			subhull_index := 0
 			// Compute the tangent point for each of the subhulls
 			for i := 0; i < n_subhulls; i++ {
@@ -169,84 +184,82 @@ func parallel_subhull_jarvis(subhull_points [][2]float32, subhull_sizes []int, g
 			// Find leftmost endpoint out of all of the subhulls
 			endpoint := 0
 			for tangent := range tangents {
-				cross := cross_prod(cur_p, tangents[endpoint], tangents[tangent]) * order
-				if (tangents[endpoint][0] == cur_p[0] && tangents[endpoint][1] == cur_p[1]) || cross > 0 {
+				turn := orient(cur_p, tangents[endpoint], tangents[tangent]) * order
+				if tangents[endpoint] == cur_p || turn > 0 {
 					// New point is to the left of current endpoint
 					endpoint = tangent
-				} else if cross == 0 && dist(cur_p, tangents[tangent]) >= dist(cur_p, tangents[endpoint]) {
-					if dist(cur_p, subhull_points[tangent]) == dist(cur_p, subhull_points[endpoint]) && endpoint != left {
-						// New point is collinear but further than current endpoint
-						endpoint = tangent
-					}
+				} else if turn == 0 && farther(cur_p, tangents[tangent], tangents[endpoint]) {
+					// New point is collinear but further than current endpoint
+					endpoint = tangent
 				}
 			}
 
 			cur_p = tangents[endpoint]
 
 			// Someone already found this point
-			if selected.get(cur_p) != nil {
+			if selected.Get(cur_p) != nil {
 				break
 			}
-			if steps == uint64(group_size-1) {
-				failed = true
+			if atomic.AddUint64(&steps, 1) >= uint64(group_size) {
+				failed.Store(true)
 				break
 			}
-			atomic.AddUint64(&steps, 1)
 		}
 	}
 
 	// Now traverse the subhulls both left and right
 	wg.Add(2)
-	go find_hull(left, 1.0)
-	go find_hull(right, 1.0)
+	go find_hull(left, 1)
+	go find_hull(right, 1)
 	wg.Wait()
 
-	if failed {
+	if failed.Load() {
 		// need to retry
 		return nil
 	}
 
 	// Get hull points from map
 	for i := 0; i < len(subhull_points); i++ {
-		if selected.get(subhull_points[i]) != nil {
+		if selected.Get(subhull_points[i]) != nil {
 			hull = append(hull, subhull_points[i])
 		}
 	}
 	return hull
 }
 
-// Enable subhull coalescing from previous iterations
-var USE_COALESCE bool
-
-// Number of iterations to run in parallel
-var SIMUL_ITERS int
-
 // Controls initial group size (2^2^3) = 256
 const INITIAL_T uint = 3
 
 // Parallel Chan's algorithm O(nlogh)
-func parallel_chans(points [][2]float32) [][2]float32 {
+// Runs simul_iters group sizes at once, reusing subhulls of smaller group sizes if coalesce
+func parallel_chans[T Coord](ctx context.Context, points [][2]T, simul_iters int, coalesce bool) ([][2]T, error) {
 	n := len(points)
-	var global_subhulls SafeMap
-	if USE_COALESCE {
+	// Stop iterations that are still running once one succeeds
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var global_subhulls SafeMap[[2]int, [2]T]
+	// Points coalescing kept out of later subhull computations (debug)
+	var points_saved int64
+	if coalesce {
 		// Initialize size of concurrent map
-		global_subhulls.init(n / 4)
+		global_subhulls.Init(n/4, hash_range)
 	}
 	// Initialize group size as 2^2^3 = 256
 	var t uint = INITIAL_T
 
-	// Channel to receive results from each iteration
-	ch := make(chan [][2]float32)
+	// Channel to receive results from each iteration, buffered so iterations never block after we return
+	ch := make(chan [][2]T, simul_iters)
 
 	for {
 		iteration := func(cur_t uint) {
-			subhulls := make([][2]float32, 0, n>>1) // n>>1 Just a guess on how many points will be in subhulls
+			subhulls := make([][2]T, 0, n>>1) // n>>1 Just a guess on how many points will be in subhulls
 			subhull_sizes := make([]int, 0, n/(1<<(1<<t))+1)
 			// Try out group size
 			group_size := 1 << (1 << cur_t)
 			if group_size == 0 {
 				fmt.Println("chan's failed, too many iterations")
 				ch <- nil
+				return
 			}
 			debug("current group size", group_size)
 			if n < group_size {
@@ -258,44 +271,51 @@ func parallel_chans(points [][2]float32) [][2]float32 {
 			 *************************************/
 			subhull_start := time.Now()
 
-			if USE_COALESCE {
-				subhulls, subhull_sizes = coalesce_subhull(&global_subhulls, points, group_size, n, subhulls, subhull_sizes)
-				debug("coalesce points saved", points_saved)
+			if coalesce {
+				subhulls, subhull_sizes = coalesce_subhull(ctx, &global_subhulls, &points_saved, points, group_size, n, subhulls, subhull_sizes)
+				debug("coalesce points saved", atomic.LoadInt64(&points_saved))
 			} else {
 				// Graham scan modifies in place so need to be careful to copy as input slice will be modified
-				copy_points := make([][2]float32, len(points))
+				copy_points := make([][2]T, len(points))
 				copy(copy_points, points)
-				subhulls, subhull_sizes = thread_pool_subhull(copy_points, group_size, n, subhulls, subhull_sizes)
+				subhulls, subhull_sizes = thread_pool_subhull(ctx, copy_points, group_size, n, subhulls, subhull_sizes)
 			}
 			debug("chan's subgroups total", time.Since(subhull_start))
+			if done(ctx) {
+				ch <- nil
+				return
+			}
 
 			/****************************************
 			 * Jarvis March (gift wrap) of subhulls *
 			 ****************************************/
 			march_start := time.Now()
-			var hull [][2]float32
+			var hull [][2]T
 			// Jarvis march meant for Chan's algorithm, operates on subhulls
-			hull = parallel_subhull_jarvis(subhulls, subhull_sizes, group_size)
+			hull = parallel_subhull_jarvis(ctx, subhulls, subhull_sizes, group_size)
 			debug("march time", time.Since(march_start))
 
 			ch <- hull
 		}
 
 		// Conduct multiple iterations at once
-		for i := 0; i < SIMUL_ITERS; i++ {
+		for i := 0; i < simul_iters; i++ {
 			go iteration(t + uint(i))
 		}
 
 		// See if any of the iterations were successful
-		for i := 0; i < SIMUL_ITERS; i++ {
+		for i := 0; i < simul_iters; i++ {
 			hull := <-ch
 			if hull != nil {
-				return hull
+				return hull, nil
 			}
 		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
 		// Group size too small
-		t += uint(SIMUL_ITERS)
+		t += uint(simul_iters)
 	}
 }
 
@@ -311,27 +331,25 @@ subhull of points[a:b], where a <= i <= j <= b. Thus we can prune based on calcu
 iterations with smaller subhull sizes.
 */
 
-var points_saved int
-
 // Represents convex hull of range [start:end] in original input points
-type Subhull struct {
+type Subhull[T Coord] struct {
 	// points on convex hull of this range
-	points [][2]float32
+	points [][2]T
 	start  int
 	end    int
 }
 
 // Parallel subhull computation that coalesces previously computed subhulls as well
-func coalesce_subhull(global_subhulls *SafeMap, points [][2]float32, group_size, n int, subhulls [][2]float32, subhull_sizes []int) ([][2]float32, []int) {
+func coalesce_subhull[T Coord](ctx context.Context, global_subhulls *SafeMap[[2]int, [2]T], points_saved *int64, points [][2]T, group_size, n int, subhulls [][2]T, subhull_sizes []int) ([][2]T, []int) {
 	num_subhulls := 0
 	// Channel to collect subhulls with metadata (range info) from workers
-	ch := make(chan Subhull)
-	worker := func(points [][2]float32, start, end int, ch chan Subhull) {
-		res := parallel_graham_scan(points)
-		ch <- Subhull{res, start, end}
+	ch := make(chan Subhull[T])
+	worker := func(points [][2]T, start, end int, ch chan Subhull[T]) {
+		res, _ := parallel_graham_scan(ctx, points)
+		ch <- Subhull[T]{res, start, end}
 	}
-	// Run graham scan on subgroups of points
-	for start := 0; start < n; start += group_size {
+	// Run graham scan on subgroups of points (only the ones started before ctx is cancelled)
+	for start := 0; start < n && !done(ctx); start += group_size {
 		end := start + group_size
 		if n < end {
 			end = n
@@ -339,8 +357,8 @@ func coalesce_subhull(global_subhulls *SafeMap, points [][2]float32, group_size,
 
 		// local_points: Points to consider for this iteration, may consist of just points from
 		// original input, or points known to be on convex hull of some subset of the pointsv
-		local_points := make([][2]float32, 0)
-		smallest_group_size := (int)((INITIAL_T >> 1) >> 1) // 256 if t=3
+		local_points := make([][2]T, 0)
+		smallest_group_size := 1 << (1 << INITIAL_T) // 256 if t=3
 
 		// Try to build subhull using old results (from first iteration, from empirical results other
 		// iterations are not as helpful, likely because results take longer to update and will be "missed"
@@ -352,16 +370,17 @@ func coalesce_subhull(global_subhulls *SafeMap, points [][2]float32, group_size,
 					local_end = n
 				}
 				// Check if map contains this range
-				previous_result := global_subhulls.get([2]float32{float32(local_start), float32(local_end)})
+				previous_result := global_subhulls.Get([2]int{local_start, local_end})
 				if previous_result != nil {
 					local_points = append(local_points, previous_result...)
-					points_saved += (local_end - local_start) - len(previous_result)
+					atomic.AddInt64(points_saved, int64((local_end-local_start)-len(previous_result)))
 				} else {
 					local_points = append(local_points, points[local_start:local_end]...)
 				}
 			}
 		} else {
-			local_points = points[start:end]
+			// Graham scan reorders its input and other iterations read the same range, so copy
+			local_points = append(local_points, points[start:end]...)
 		}
 
 		// Compute convex hull of subgroup using graham-scan
@@ -380,7 +399,7 @@ func coalesce_subhull(global_subhulls *SafeMap, points [][2]float32, group_size,
 		subhull_compute += time.Since(subhull_start)
 
 		// Update the result so future iterations can use
-		global_subhulls.put([2]float32{float32(res.start), float32(res.end)}, res.points)
+		global_subhulls.Put([2]int{res.start, res.end}, res.points)
 
 		append_start := time.Now()
 		// Aggregate into a single array for performance
@@ -0,0 +1,448 @@
+package hull
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ParseOptions controls how malformed input lines are handled
+type ParseOptions struct {
+	// Skip malformed lines (and count them) instead of stopping at the first one
+	SkipInvalid bool
+	// Goroutines parsing chunks of the input in parallel, GOMAXPROCS if <= 0
+	Workers int
+}
+
+// ParseError describes a malformed line of a point file
+type ParseError struct {
+	File string
+	// 1-based line and column of the problem
+	Line   int
+	Column int
+	// Offending line
+	Text string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %v: %q", e.File, e.Line, e.Column, e.Err, e.Text)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// Line does not have one comma separated coordinate per dimension ("x,y" or "x,y,z")
+	ErrFieldCount = errors.New("wrong number of comma separated coordinates")
+	// Coordinate is NaN or infinite
+	ErrNotFinite = errors.New("coordinate is not finite")
+)
+
+// ParseFile reads "x,y" lines of T coordinates from filename ("-" for stdin), see ReadPoints
+func ParseFile[T Coord](filename string, opts ParseOptions) (points [][2]T, skipped int, err error) {
+	if filename == "-" {
+		return ReadPoints[T](os.Stdin, "stdin", opts)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+	return ReadPoints[T](file, filename, opts)
+}
+
+// ParseFile3D reads "x,y,z" lines of T coordinates from filename ("-" for stdin), see ReadPoints
+func ParseFile3D[T Coord](filename string, opts ParseOptions) (points [][3]T, skipped int, err error) {
+	if filename == "-" {
+		return ReadPoints3D[T](os.Stdin, "stdin", opts)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+	return ReadPoints3D[T](file, filename, opts)
+}
+
+// ParseFileD reads lines of dim comma separated T coordinates from filename ("-" for stdin), see
+// ReadPoints
+func ParseFileD[T Coord](filename string, dim int, opts ParseOptions) (points [][]T, skipped int, err error) {
+	if filename == "-" {
+		return ReadPointsD[T](os.Stdin, "stdin", dim, opts)
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+	return ReadPointsD[T](file, filename, dim, opts)
+}
+
+// Bytes read from the input at a time (grown if a single line is longer)
+const read_chunk_size = 1 << 22
+
+// Points parsed from one chunk of input
+type chunk_result[P any] struct {
+	points  []P
+	skipped int
+	// Lines in the chunk, to turn chunk line numbers into input line numbers
+	lines int
+	err   *ParseError
+}
+
+// ReadPoints reads "x,y" lines of T coordinates from r in a single pass, name is used in errors.
+// Blank lines are ignored. Integer types need integer text, floats are rounded to the nearest T.
+// A malformed line is returned as a *ParseError, or counted in skipped if opts.SkipInvalid.
+// Input is read in large chunks split on newlines, which are parsed in parallel.
+func ReadPoints[T Coord](r io.Reader, name string, opts ParseOptions) (points [][2]T, skipped int, err error) {
+	return read_records(r, name, opts, parse_point[T])
+}
+
+// ReadPoints3D reads "x,y,z" lines of T coordinates from r, otherwise like ReadPoints
+func ReadPoints3D[T Coord](r io.Reader, name string, opts ParseOptions) (points [][3]T, skipped int, err error) {
+	return read_records(r, name, opts, parse_point3d[T])
+}
+
+// ReadPointsD reads lines of dim comma separated T coordinates from r, otherwise like ReadPoints
+func ReadPointsD[T Coord](r io.Reader, name string, dim int, opts ParseOptions) (points [][]T, skipped int, err error) {
+	if dim < 2 || dim > MaxDimension {
+		return nil, 0, fmt.Errorf("hull: unsupported dimension %d", dim)
+	}
+	return read_records(r, name, opts, func(line string) ([]T, int, error) {
+		point := make([]T, dim)
+		column, err := parse_fields(line, point)
+		return point, column, err
+	})
+}
+
+// Read lines of r as points P with parse, see ReadPoints
+func read_records[P any](r io.Reader, name string, opts ParseOptions, parse func(string) (P, int, error)) (points []P, skipped int, err error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// Each chunk is sent with the result it should fill, results are kept in input order
+	type chunk struct {
+		text   string
+		result *chunk_result[P]
+	}
+	chunks := make(chan chunk, workers)
+	var results []*chunk_result[P]
+	// Set on the first malformed line when failing fast, so no more input is read
+	var failed atomic.Bool
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range chunks {
+				*c.result = parse_lines(c.text, name, opts, parse)
+				if c.result.err != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	// Read chunks ending on a newline, carrying the partial last line over to the next chunk
+	var read_err error
+	buf := make([]byte, read_chunk_size)
+	filled := 0
+	for !failed.Load() {
+		n, err := io.ReadFull(r, buf[filled:])
+		filled += n
+		eof := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !eof {
+			read_err = fmt.Errorf("%s: %w", name, err)
+			break
+		}
+
+		cut := filled
+		if !eof {
+			cut = bytes.LastIndexByte(buf[:filled], '\n') + 1
+			if cut == 0 {
+				// Line longer than the buffer
+				buf = append(buf, make([]byte, len(buf))...)
+				continue
+			}
+		}
+		if cut > 0 {
+			results = append(results, &chunk_result[P]{})
+			chunks <- chunk{string(buf[:cut]), results[len(results)-1]}
+		}
+		if eof {
+			break
+		}
+		filled = copy(buf, buf[cut:filled])
+	}
+	close(chunks)
+	wg.Wait()
+	if read_err != nil {
+		return nil, 0, read_err
+	}
+
+	// Fix up line numbers and report the first error in the input
+	line_offset := 0
+	total := 0
+	for _, res := range results {
+		if res.err != nil {
+			res.err.Line += line_offset
+			return nil, skipped, res.err
+		}
+		line_offset += res.lines
+		skipped += res.skipped
+		total += len(res.points)
+	}
+
+	points = make([]P, 0, total)
+	for _, res := range results {
+		points = append(points, res.points...)
+	}
+	return points, skipped, nil
+}
+
+// Parse the complete lines in text, line numbers in the result are relative to text
+func parse_lines[P any](text string, name string, opts ParseOptions, parse func(string) (P, int, error)) chunk_result[P] {
+	// Lines are usually around 18 bytes ("0.123456,0.654321")
+	res := chunk_result[P]{points: make([]P, 0, len(text)/16)}
+	for len(text) > 0 {
+		res.lines++
+		line := text
+		if end := strings.IndexByte(text, '\n'); end != -1 {
+			line, text = text[:end], text[end+1:]
+		} else {
+			text = ""
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		point, column, err := parse(line)
+		if err != nil {
+			if opts.SkipInvalid {
+				res.skipped++
+				continue
+			}
+			res.err = &ParseError{name, res.lines, column, strings.TrimSuffix(line, "\r"), err}
+			return res
+		}
+		res.points = append(res.points, point)
+	}
+	return res
+}
+
+// Parse an "x,y" line (surrounding spaces and a trailing \r are allowed),
+// on error also returns the 1-based column of the bad field
+func parse_point[T Coord](line string) ([2]T, int, error) {
+	var point [2]T
+	column, err := parse_fields(line, point[:])
+	return point, column, err
+}
+
+// Parse an "x,y,z" line like parse_point
+func parse_point3d[T Coord](line string) ([3]T, int, error) {
+	var point [3]T
+	column, err := parse_fields(line, point[:])
+	return point, column, err
+}
+
+// Parse exactly len(point) comma separated coordinates from line into point,
+// on error returns the 1-based column of the bad field
+func parse_fields[T Coord](line string, point []T) (int, error) {
+	line = strings.TrimSuffix(line, "\r")
+
+	// Field boundaries, checking the count before parsing any values
+	var ends [8]int
+	fields := ends[:0]
+	for start := 0; ; {
+		comma := strings.IndexByte(line[start:], ',')
+		if comma == -1 {
+			break
+		}
+		if len(fields) == len(point)-1 {
+			// Extra field
+			return start + comma + 1, ErrFieldCount
+		}
+		fields = append(fields, start+comma)
+		start += comma + 1
+	}
+	if len(fields) < len(point)-1 {
+		return len(line) + 1, ErrFieldCount
+	}
+	fields = append(fields, len(line))
+
+	start := 0
+	for i, end := range fields {
+		field := line[start:end]
+		// Column of the first non-space character of the field
+		column := start + len(field) - len(strings.TrimLeft(field, " \t")) + 1
+		value, err := parse_coord[T](strings.TrimSpace(field))
+		if err != nil {
+			return column, err
+		}
+		point[i] = value
+		start = end + 1
+	}
+	return 0, nil
+}
+
+// Parse a single coordinate of type T
+func parse_coord[T Coord](field string) (T, error) {
+	bit_size := 8 * coord_type[T]().Size()
+	if !is_float[T]() {
+		value, err := strconv.ParseInt(field, 10, bit_size)
+		if err != nil {
+			// Keep ErrSyntax/ErrRange, the line is already in the ParseError
+			return 0, err.(*strconv.NumError).Err
+		}
+		return T(value), nil
+	}
+	value, err := strconv.ParseFloat(field, bit_size)
+	if err != nil {
+		return 0, err.(*strconv.NumError).Err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, ErrNotFinite
+	}
+	return T(value), nil
+}
+
+// OutputPoints writes points to filename, one "x,y" pair per line. Floating point coordinates are
+// written with the fewest digits that read back as the same value.
+func OutputPoints[T Coord](filename string, points [][2]T) error {
+	return output_points(filename, coords_of[T](points, 2), 2)
+}
+
+// OutputPoints3D writes 3D points to filename, one "x,y,z" line each, like OutputPoints
+func OutputPoints3D[T Coord](filename string, points [][3]T) error {
+	return output_points(filename, coords_of[T](points, 3), 3)
+}
+
+// OutputPointsD writes points with dim coordinates each to filename, one comma separated line per
+// point, like OutputPoints
+func OutputPointsD[T Coord](filename string, dim int, points [][]T) error {
+	coords, err := join_points(points, dim)
+	if err != nil {
+		return err
+	}
+	return output_points(filename, coords, dim)
+}
+
+// Write the coordinates of dim dimensional points to filename, one comma separated point per line
+func output_points[T Coord](filename string, coords []T, dim int) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	var line []byte
+	for start := 0; start < len(coords); start += dim {
+		line = line[:0]
+		for i, x := range coords[start : start+dim] {
+			if i > 0 {
+				line = append(line, ',')
+			}
+			line = append_coord(line, x)
+		}
+		line = append(line, '\n')
+		w.Write(line)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// OutputOBJ writes a 3D hull to filename as a Wavefront OBJ mesh: a "v x y z" line per vertex, then
+// an "f i j k" line per face with 1-based vertex indices
+func OutputOBJ[T Coord](filename string, h *Hull3D[T]) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	var line []byte
+	for _, v := range h.Vertices {
+		line = append(line[:0], 'v')
+		for _, x := range v {
+			line = append(line, ' ')
+			line = append_coord(line, x)
+		}
+		line = append(line, '\n')
+		w.Write(line)
+	}
+	for _, face := range h.Faces {
+		line = append(line[:0], 'f')
+		for _, v := range face {
+			line = append(line, ' ')
+			line = strconv.AppendInt(line, int64(v+1), 10)
+		}
+		line = append(line, '\n')
+		w.Write(line)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// OutputHullD writes a d-dimensional hull to filename, one line per facet: its comma separated vertex
+// indices (0-based, into the input points), a space, the comma separated coordinates of its outward
+// normal, a space and its offset
+func OutputHullD(filename string, h *HullD) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	var line []byte
+	for _, facet := range h.Facets {
+		line = line[:0]
+		for i, v := range facet.Vertices {
+			if i > 0 {
+				line = append(line, ',')
+			}
+			line = strconv.AppendInt(line, int64(v), 10)
+		}
+		for i, x := range facet.Normal {
+			if i == 0 {
+				line = append(line, ' ')
+			} else {
+				line = append(line, ',')
+			}
+			line = append_coord(line, x)
+		}
+		line = append(line, ' ')
+		line = append_coord(line, facet.Offset)
+		line = append(line, '\n')
+		w.Write(line)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Append the text form of x to b
+func append_coord[T Coord](b []byte, x T) []byte {
+	if !is_float[T]() {
+		return strconv.AppendInt(b, int64(x), 10)
+	}
+	return strconv.AppendFloat(b, float64(x), 'g', -1, 8*coord_type[T]().Size())
+}
@@ -0,0 +1,160 @@
+package hull
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// Write text to a file in a test directory, returns its name
+func temp_file(t *testing.T, name, text string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(text), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestParseFileErrors(t *testing.T) {
+	tests := []struct {
+		text         string
+		line, column int
+		err          error
+		bad          string
+	}{
+		{"1,2\n3,x\n", 2, 3, strconv.ErrSyntax, "3,x"},
+		{"1,2\n\n 4, 5,6\n", 3, 6, ErrFieldCount, " 4, 5,6"},
+		{"5\n", 1, 2, ErrFieldCount, "5"},
+		{"1,2,\n", 1, 4, ErrFieldCount, "1,2,"},
+		{"1,\n", 1, 3, strconv.ErrSyntax, "1,"},
+		{",1\n", 1, 1, strconv.ErrSyntax, ",1"},
+		{"0,0\n1,  nan\n", 2, 5, ErrNotFinite, "1,  nan"},
+		{"0,0\r\n1,1\r\n2,y\r\n", 3, 3, strconv.ErrSyntax, "2,y"},
+		// No trailing newline
+		{"0,0\n1,1\n\t2 ,3 3", 3, 5, strconv.ErrSyntax, "\t2 ,3 3"},
+	}
+	for _, test := range tests {
+		filename := temp_file(t, "points.txt", test.text)
+		_, _, err := ParseFile[float64](filename, ParseOptions{})
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("%q: got %v, want a *ParseError", test.text, err)
+		}
+		if perr.File != filename || perr.Line != test.line || perr.Column != test.column || perr.Text != test.bad || !errors.Is(err, test.err) {
+			t.Errorf("%q: got %s:%d:%d %q %v, want %s:%d:%d %q %v", test.text, perr.File, perr.Line, perr.Column, perr.Text, perr.Err,
+				filename, test.line, test.column, test.bad, test.err)
+		}
+
+		// The same lines are skipped and counted when asked
+		_, skipped, err := ParseFile[float64](filename, ParseOptions{SkipInvalid: true})
+		if err != nil || skipped != 1 {
+			t.Errorf("%q: skipping got %d skipped, %v, want 1 skipped", test.text, skipped, err)
+		}
+	}
+}
+
+func TestParseFileIntegerErrors(t *testing.T) {
+	filename := temp_file(t, "points.txt", "1,2\n1, 99999999999\n1.5,2\n")
+	_, _, err := ParseFile[int32](filename, ParseOptions{})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Line != 2 || perr.Column != 4 || !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("got %v, want %s:2:4 out of range", err, filename)
+	}
+	_, skipped, err := ParseFile[int32](filename, ParseOptions{SkipInvalid: true})
+	if err != nil || skipped != 2 {
+		t.Fatalf("skipping got %d skipped, %v, want 2 skipped", skipped, err)
+	}
+}
+
+func TestParseFile3DErrors(t *testing.T) {
+	filename := temp_file(t, "points.txt", "1,2,3\n4,5\n")
+	_, _, err := ParseFile3D[float32](filename, ParseOptions{})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Line != 2 || perr.Column != 4 || !errors.Is(err, ErrFieldCount) {
+		t.Fatalf("got %v, want %s:2:4 wrong number of coordinates", err, filename)
+	}
+}
+
+// Parse text one line at a time, the reference for the chunked parser
+func serial_parse(t *testing.T, text string) [][2]float64 {
+	t.Helper()
+	var points [][2]float64
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		p, _, err := parse_point[float64](line)
+		if err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// Lines of varying length, several read chunks long, so lines straddle chunk boundaries
+func chunked_input(r *rand.Rand, newline string, trailing bool) string {
+	var b strings.Builder
+	for b.Len() < 2*read_chunk_size+read_chunk_size/2 {
+		switch r.Intn(50) {
+		case 0:
+			b.WriteString(newline)
+		case 1:
+			fmt.Fprintf(&b, "  %d , %d ", r.Intn(1000), -r.Intn(1000))
+		default:
+			fmt.Fprintf(&b, "%v,%v", r.NormFloat64()*math.Pow(10, float64(r.Intn(12)-6)), r.Float64())
+		}
+		b.WriteString(newline)
+	}
+	// A line longer than a whole chunk
+	b.WriteString(strings.Repeat(" ", read_chunk_size+100) + "7,8")
+	if trailing {
+		b.WriteString(newline)
+	}
+	return b.String()
+}
+
+func TestReadPointsChunks(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, newline := range []string{"\n", "\r\n"} {
+		for _, trailing := range []bool{true, false} {
+			text := chunked_input(r, newline, trailing)
+			want := serial_parse(t, text)
+			for _, workers := range []int{1, 4} {
+				// Short reads, so chunks are not filled in one go
+				got, skipped, err := ReadPoints[float64](iotest.HalfReader(strings.NewReader(text)), "in", ParseOptions{Workers: workers})
+				if err != nil || skipped != 0 {
+					t.Fatalf("newline %q trailing %v workers %d: %d skipped, %v", newline, trailing, workers, skipped, err)
+				}
+				if !slices.Equal(got, want) {
+					t.Fatalf("newline %q trailing %v workers %d: %d points differ from a serial parse of %d", newline, trailing, workers, len(got), len(want))
+				}
+			}
+		}
+	}
+}
+
+// Errors past the first chunk report their line in the whole input
+func TestReadPointsChunkErrorLine(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	text := chunked_input(r, "\r\n", true)
+	lines := strings.Count(text, "\n")
+	for _, bad_line := range []int{lines / 3, lines/2 + 1, lines - 1} {
+		bad := strings.Split(text, "\r\n")
+		bad[bad_line-1] = "1,2,3"
+		_, _, err := ReadPoints[float64](strings.NewReader(strings.Join(bad, "\r\n")), "in", ParseOptions{Workers: 4})
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Line != bad_line || perr.Column != 4 || perr.Text != "1,2,3" {
+			t.Fatalf("got %v, want in:%d:4", err, bad_line)
+		}
+	}
+}
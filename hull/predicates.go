@@ -0,0 +1,336 @@
+package hull
+
+import (
+	"cmp"
+	"math"
+	"math/big"
+	"math/bits"
+	"unsafe"
+)
+
+// Coord is a coordinate type the algorithms can run on. Every predicate is exact for all of them.
+type Coord interface {
+	float32 | float64 | int32 | int64
+}
+
+/************************
+ * Robust orientation   *
+ ************************
+Orientation tests decide every turn in the algorithms, so rounding errors in them show up as
+missing or extra hull points (and as non-terminating wraps). orient evaluates the determinant
+in float64 and only trusts the sign when it is larger than a bound on the accumulated rounding
+error (Shewchuk, "Adaptive Precision Floating-Point Arithmetic and Fast Robust Geometric Predicates").
+Nearly degenerate cases fall back to exact arithmetic.
+
+float32, int32 and float64 coordinates are all exact as float64 and share this path. int64
+coordinates are not, so they use exact 128-bit integer arithmetic instead.
+*/
+
+// Machine epsilon for float64 in Shewchuk's convention (half an ulp of 1)
+const epsilon = 1.0 / (1 << 53)
+
+// Relative error bound for the float64 orientation determinant
+const ccw_err_bound = (3.0 + 16.0*epsilon) * epsilon
+
+// Whether T is int64. Written so the compiler folds it to a constant in each instantiation,
+// a type switch here would cost more than the orientation test itself.
+func is_int64[T Coord]() bool {
+	var one T = 1
+	return unsafe.Sizeof(one) == 8 && one/2 == 0
+}
+
+// Whether T is float64, the only type whose float64 products can overflow or underflow
+func is_float64[T Coord]() bool {
+	var one T = 1
+	return unsafe.Sizeof(one) == 8 && one/2 != 0
+}
+
+// Orientation of c relative to the directed line a->b: 1 if c is counterclockwise (left) of ab,
+// -1 if clockwise (right), 0 if collinear. Exact for all inputs.
+func orient[T Coord](a, b, c [2]T) int {
+	return cross_sign(a, b, a, c)
+}
+
+// Sign of the cross product (b - a) x (d - c), exact for all inputs
+func cross_sign[T Coord](a, b, c, d [2]T) int {
+	if is_int64[T]() {
+		return cross_sign_int64(
+			[2]int64{int64(a[0]), int64(a[1])}, [2]int64{int64(b[0]), int64(b[1])},
+			[2]int64{int64(c[0]), int64(c[1])}, [2]int64{int64(d[0]), int64(d[1])})
+	}
+	detleft := (float64(b[0]) - float64(a[0])) * (float64(d[1]) - float64(c[1]))
+	detright := (float64(b[1]) - float64(a[1])) * (float64(d[0]) - float64(c[0]))
+	det := detleft - detright
+
+	// float64 inputs can overflow (or underflow, which the error bound does not cover)
+	if is_float64[T]() && (!filter_safe(detleft) || !filter_safe(detright)) {
+		return cross_sign_exact(to_float64(a), to_float64(b), to_float64(c), to_float64(d))
+	}
+
+	// Filter - if the two terms have different signs there is no cancellation
+	var detsum float64
+	if detleft > 0 {
+		if detright <= 0 {
+			return sign(det)
+		}
+		detsum = detleft + detright
+	} else if detleft < 0 {
+		if detright >= 0 {
+			return sign(det)
+		}
+		detsum = -detleft - detright
+	} else {
+		return sign(det)
+	}
+
+	err_bound := ccw_err_bound * detsum
+	if det >= err_bound || -det >= err_bound {
+		return sign(det)
+	}
+	return cross_sign_exact(to_float64(a), to_float64(b), to_float64(c), to_float64(d))
+}
+
+// Point converted to float64, exact for every type but int64
+func to_float64[T Coord](p [2]T) [2]float64 {
+	return [2]float64{float64(p[0]), float64(p[1])}
+}
+
+// Whether a float64 product can be trusted by the filter: finite, and not small enough to have lost
+// precision to underflow (0 is fine only if it is exact, which the exact path checks)
+func filter_safe(x float64) bool {
+	return x != 0 && math.Abs(x) > min_exact_product && math.Abs(x) <= math.MaxFloat64
+}
+
+// Exact cross product sign for when the float filter fails. Exactly collinear inputs (common on
+// grids) end up here, so the usual case of exactly representable differences is done with
+// float64 expansions and only the rest goes to arbitrary precision arithmetic.
+func cross_sign_exact(a, b, c, d [2]float64) int {
+	x1, x1_err := two_diff(b[0], a[0])
+	y2, y2_err := two_diff(d[1], c[1])
+	y1, y1_err := two_diff(b[1], a[1])
+	x2, x2_err := two_diff(d[0], c[0])
+	if x1_err != 0 || y2_err != 0 || y1_err != 0 || x2_err != 0 {
+		return cross_sign_big(a, b, c, d)
+	}
+	if sign, ok := diff_products_sign(x1, y2, y1, x2); ok {
+		return sign
+	}
+	// Products overflow or underflow, scaling both factors of each product by powers of two keeps the sign
+	x1, y1, ok1 := scale_pair(x1, y1)
+	y2, x2, ok2 := scale_pair(y2, x2)
+	if ok1 && ok2 {
+		if sign, ok := diff_products_sign(x1, y2, y1, x2); ok {
+			return sign
+		}
+	}
+	return cross_sign_big(a, b, c, d)
+}
+
+// Sign of x1*y2 - y1*x2 using float64 expansions, if no product overflows or underflows
+func diff_products_sign(x1, y2, y1, x2 float64) (int, bool) {
+	left, left_err := two_product(x1, y2)
+	right, right_err := two_product(y1, x2)
+	if !exact_product(x1, y2, left) || !exact_product(y1, x2, right) {
+		return 0, false
+	}
+	return expansion_sign(two_two_diff(left, left_err, right, right_err)), true
+}
+
+// Scale x and y by the same power of two so the larger magnitude is in [0.5, 1), and whether that was exact
+func scale_pair(x, y float64) (float64, float64, bool) {
+	_, exp := math.Frexp(math.Max(math.Abs(x), math.Abs(y)))
+	sx, sy := math.Ldexp(x, -exp), math.Ldexp(y, -exp)
+	return sx, sy, math.Ldexp(sx, exp) == x && math.Ldexp(sy, exp) == y
+}
+
+// Whether two_product(x, y) with result p is exact: finite, and not so small that its error term underflowed
+func exact_product(x, y, p float64) bool {
+	if p == 0 {
+		return x == 0 || y == 0
+	}
+	return math.Abs(p) > min_exact_product && math.Abs(p) <= math.MaxFloat64
+}
+
+// float64 values span exponents -1074 to 1023, so the difference of two never needs more bits than this
+const exact_diff_prec = 1024 + 1074 + 1
+
+// Difference of two float64s as an exact big.Float
+func exact_diff(x, y float64) *big.Float {
+	return new(big.Float).SetPrec(exact_diff_prec).Sub(big.NewFloat(x), big.NewFloat(y))
+}
+
+// Cross product sign in big.Float arithmetic, with enough precision to be exact for any finite inputs
+func cross_sign_big(a, b, c, d [2]float64) int {
+	left := new(big.Float).SetPrec(2*exact_diff_prec).Mul(exact_diff(b[0], a[0]), exact_diff(d[1], c[1]))
+	right := new(big.Float).SetPrec(2*exact_diff_prec).Mul(exact_diff(b[1], a[1]), exact_diff(d[0], c[0]))
+	return left.Cmp(right)
+}
+
+// Smallest product magnitude whose rounding error is still representable
+const min_exact_product = 0x1p-900
+
+// x + y = a + b exactly, x = fl(a + b)
+func two_sum(a, b float64) (x, y float64) {
+	x = a + b
+	bv := x - a
+	av := x - bv
+	return x, (a - av) + (b - bv)
+}
+
+// x + y = a - b exactly, x = fl(a - b)
+func two_diff(a, b float64) (x, y float64) {
+	x = a - b
+	bv := a - x
+	av := x + bv
+	return x, (a - av) + (bv - b)
+}
+
+// x + y = a * b exactly, x = fl(a * b)
+func two_product(a, b float64) (x, y float64) {
+	x = a * b
+	return x, math.FMA(a, b, -x)
+}
+
+// (a1 + a0) - (b1 + b0) as a nonoverlapping expansion, most significant component first
+func two_two_diff(a1, a0, b1, b0 float64) [4]float64 {
+	j, x0 := two_diff(a0, b0)
+	j, k := two_sum(a1, j)
+	i, x1 := two_diff(k, b1)
+	x3, x2 := two_sum(j, i)
+	return [4]float64{x3, x2, x1, x0}
+}
+
+// Sign of a nonoverlapping expansion is the sign of its most significant nonzero component
+func expansion_sign(e [4]float64) int {
+	for _, x := range e {
+		if x != 0 {
+			return sign(x)
+		}
+	}
+	return 0
+}
+
+// Cross product sign for int64 coordinates: differences and products in 128-bit integers,
+// or big.Int if a difference overflows int64
+func cross_sign_int64(a, b, c, d [2]int64) int {
+	x1, ok1 := sub_int64(b[0], a[0])
+	y2, ok2 := sub_int64(d[1], c[1])
+	y1, ok3 := sub_int64(b[1], a[1])
+	x2, ok4 := sub_int64(d[0], c[0])
+	if ok1 && ok2 && ok3 && ok4 {
+		return cmp_products(x1, y2, y1, x2)
+	}
+
+	diff := func(x, y int64) *big.Int {
+		return new(big.Int).Sub(big.NewInt(x), big.NewInt(y))
+	}
+	left := new(big.Int).Mul(diff(b[0], a[0]), diff(d[1], c[1]))
+	right := new(big.Int).Mul(diff(b[1], a[1]), diff(d[0], c[0]))
+	return left.Cmp(right)
+}
+
+// x - y, and whether it did not overflow
+func sub_int64(x, y int64) (int64, bool) {
+	d := x - y
+	return d, (x^y) >= 0 || (x^d) >= 0
+}
+
+// Sign of a*b - c*d, exact
+func cmp_products(a, b, c, d int64) int {
+	left_sign := sign_int64(a) * sign_int64(b)
+	right_sign := sign_int64(c) * sign_int64(d)
+	if left_sign != right_sign {
+		// Products have different signs (or one is 0), so the order of the signs decides
+		return cmp.Compare(left_sign, right_sign)
+	}
+	if left_sign == 0 {
+		return 0
+	}
+	left_hi, left_lo := bits.Mul64(abs_int64(a), abs_int64(b))
+	right_hi, right_lo := bits.Mul64(abs_int64(c), abs_int64(d))
+	magnitude := cmp.Compare(left_hi, right_hi)
+	if magnitude == 0 {
+		magnitude = cmp.Compare(left_lo, right_lo)
+	}
+	return left_sign * magnitude
+}
+
+// |x| as a uint64, correct for math.MinInt64 too
+func abs_int64(x int64) uint64 {
+	if x < 0 {
+		return uint64(-x)
+	}
+	return uint64(x)
+}
+
+func sign_int64(x int64) int {
+	return cmp.Compare(x, 0)
+}
+
+// Sign of (b - a) . (d - c) for d - c parallel to b - a, i.e. whether d is ahead of c in direction ab
+func along[T Coord](a, b, c, d [2]T) int {
+	if a[0] != b[0] {
+		return cmp.Compare(d[0], c[0]) * cmp.Compare(b[0], a[0])
+	}
+	return cmp.Compare(d[1], c[1]) * cmp.Compare(b[1], a[1])
+}
+
+// Sign of the dot product (b - a) . (d - c), exact for all inputs. Rotating d - c by 90 degrees turns
+// it into a cross product, and the rotated vector is the difference of two points made by swapping
+// coordinates of c and d, so no coordinate is negated.
+func dot_sign[T Coord](a, b, c, d [2]T) int {
+	return cross_sign(a, b, [2]T{d[1], c[0]}, [2]T{c[1], d[0]})
+}
+
+// Whether a is further from p than b, for a, b and p collinear (used to break orientation ties).
+// On a line through p the distances compare like either coordinate's distance, so compare |x| and
+// fall back to |y| for a vertical line.
+func farther[T Coord](p, a, b [2]T) bool {
+	i := 0
+	if a[0] == p[0] && b[0] == p[0] {
+		i = 1
+	}
+	return cmp_abs_diff(a[i], p[i], b[i], p[i]) > 0
+}
+
+// Sign of |a - p| - |b - q|, exact
+func cmp_abs_diff[T Coord](a, p, b, q T) int {
+	if is_int64[T]() {
+		// Differences of int64 fit in a uint64 magnitude
+		mag := func(x, y T) uint64 {
+			if x >= y {
+				return uint64(int64(x)) - uint64(int64(y))
+			}
+			return uint64(int64(y)) - uint64(int64(x))
+		}
+		return cmp.Compare(mag(a, p), mag(b, q))
+	}
+	// Exact differences as (rounded, error) pairs
+	ad, ad_err := two_diff(float64(a), float64(p))
+	bd, bd_err := two_diff(float64(b), float64(q))
+	if math.IsInf(ad, 0) || math.IsInf(bd, 0) {
+		left := exact_diff(float64(a), float64(p))
+		right := exact_diff(float64(b), float64(q))
+		return left.Abs(left).Cmp(right.Abs(right))
+	}
+	if ad < 0 {
+		ad, ad_err = -ad, -ad_err
+	}
+	if bd < 0 {
+		bd, bd_err = -bd, -bd_err
+	}
+	if ad != bd {
+		return cmp.Compare(ad, bd)
+	}
+	return cmp.Compare(ad_err, bd_err)
+}
+
+// Sign of a float as -1, 0 or 1
+func sign(x float64) int {
+	if x > 0 {
+		return 1
+	} else if x < 0 {
+		return -1
+	}
+	return 0
+}
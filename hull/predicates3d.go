@@ -0,0 +1,132 @@
+package hull
+
+import (
+	"math"
+	"math/big"
+)
+
+/************************
+ * 3D orientation       *
+ ************************
+Same approach as orient: a float64 determinant with Shewchuk's error bound, and exact arithmetic
+when the bound can't decide. Differences of int64 coordinates are not exact in float64, so int64
+points only use the filter when every coordinate is small enough to convert exactly.
+*/
+
+// Relative error bound for the float64 3D orientation determinant
+const o3d_err_bound = (7.0 + 56.0*epsilon) * epsilon
+
+// Precision for exact 3D determinants of float64 differences: products of three differences
+// span at most 3*exact_diff_prec bits, a few more cover the carries of the sums
+const exact3_prec = 3*exact_diff_prec + 8
+
+// Largest magnitude of an int64 coordinate that converts to float64 exactly
+const max_exact_int64 = 1 << 53
+
+// Orientation of d relative to the plane through a, b and c: 1 if d is on the side the normal
+// (b - a) x (c - a) points to (a, b, c counterclockwise seen from d), -1 if on the other side, 0 if
+// coplanar. Exact for all inputs.
+func orient3d[T Coord](a, b, c, d [3]T) int {
+	if is_int64[T]() && !exact_float64_int64(a, b, c, d) {
+		return orient3d_int64(a, b, c, d)
+	}
+	bax, bay, baz := float64(b[0])-float64(a[0]), float64(b[1])-float64(a[1]), float64(b[2])-float64(a[2])
+	cax, cay, caz := float64(c[0])-float64(a[0]), float64(c[1])-float64(a[1]), float64(c[2])-float64(a[2])
+	dax, day, daz := float64(d[0])-float64(a[0]), float64(d[1])-float64(a[1]), float64(d[2])-float64(a[2])
+
+	cay_daz, caz_day := cay*daz, caz*day
+	caz_dax, cax_daz := caz*dax, cax*daz
+	cax_day, cay_dax := cax*day, cay*dax
+	det := bax*(cay_daz-caz_day) + bay*(caz_dax-cax_daz) + baz*(cax_day-cay_dax)
+	permanent := (math.Abs(cay_daz)+math.Abs(caz_day))*math.Abs(bax) +
+		(math.Abs(caz_dax)+math.Abs(cax_daz))*math.Abs(bay) +
+		(math.Abs(cax_day)+math.Abs(cay_dax))*math.Abs(baz)
+
+	// float64 inputs can overflow or underflow, which the error bound does not cover
+	if is_float64[T]() && !(permanent > min_exact_product && permanent <= math.MaxFloat64) {
+		return orient3d_exact(a, b, c, d)
+	}
+	if permanent == 0 {
+		// Every product is exactly 0
+		return 0
+	}
+	err_bound := o3d_err_bound * permanent
+	if det > err_bound || -det > err_bound {
+		return sign(det)
+	}
+	return orient3d_exact(a, b, c, d)
+}
+
+// Whether every coordinate of the int64 points converts to float64 exactly
+func exact_float64_int64[T Coord](points ...[3]T) bool {
+	for _, p := range points {
+		for _, x := range p {
+			if int64(x) > max_exact_int64 || int64(x) < -max_exact_int64 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Approximate 3D orientation determinant, for picking points far from a plane
+func orient3d_approx[T Coord](a, b, c, d [3]T) float64 {
+	bax, bay, baz := float64(b[0])-float64(a[0]), float64(b[1])-float64(a[1]), float64(b[2])-float64(a[2])
+	cax, cay, caz := float64(c[0])-float64(a[0]), float64(c[1])-float64(a[1]), float64(c[2])-float64(a[2])
+	dax, day, daz := float64(d[0])-float64(a[0]), float64(d[1])-float64(a[1]), float64(d[2])-float64(a[2])
+	return bax*(cay*daz-caz*day) + bay*(caz*dax-cax*daz) + baz*(cax*day-cay*dax)
+}
+
+// 3D orientation in big.Float arithmetic, with enough precision to be exact for any finite inputs
+func orient3d_exact[T Coord](a, b, c, d [3]T) int {
+	diff := func(p, q [3]T, i int) *big.Float {
+		return exact_diff(float64(p[i]), float64(q[i]))
+	}
+	mul := func(x, y *big.Float) *big.Float {
+		return new(big.Float).SetPrec(exact3_prec).Mul(x, y)
+	}
+	sub := func(x, y *big.Float) *big.Float {
+		return new(big.Float).SetPrec(exact3_prec).Sub(x, y)
+	}
+	bax, bay, baz := diff(b, a, 0), diff(b, a, 1), diff(b, a, 2)
+	cax, cay, caz := diff(c, a, 0), diff(c, a, 1), diff(c, a, 2)
+	dax, day, daz := diff(d, a, 0), diff(d, a, 1), diff(d, a, 2)
+
+	det := mul(bax, sub(mul(cay, daz), mul(caz, day)))
+	det.Add(det, mul(bay, sub(mul(caz, dax), mul(cax, daz))))
+	det.Add(det, mul(baz, sub(mul(cax, day), mul(cay, dax))))
+	return det.Sign()
+}
+
+// 3D orientation for int64 coordinates in big.Int arithmetic
+func orient3d_int64[T Coord](a, b, c, d [3]T) int {
+	diff := func(p, q [3]T, i int) *big.Int {
+		return new(big.Int).Sub(big.NewInt(int64(p[i])), big.NewInt(int64(q[i])))
+	}
+	mul := func(x, y *big.Int) *big.Int {
+		return new(big.Int).Mul(x, y)
+	}
+	sub := func(x, y *big.Int) *big.Int {
+		return new(big.Int).Sub(x, y)
+	}
+	bax, bay, baz := diff(b, a, 0), diff(b, a, 1), diff(b, a, 2)
+	cax, cay, caz := diff(c, a, 0), diff(c, a, 1), diff(c, a, 2)
+	dax, day, daz := diff(d, a, 0), diff(d, a, 1), diff(d, a, 2)
+
+	det := mul(bax, sub(mul(cay, daz), mul(caz, day)))
+	det.Add(det, mul(bay, sub(mul(caz, dax), mul(cax, daz))))
+	det.Add(det, mul(baz, sub(mul(cax, day), mul(cay, dax))))
+	return det.Sign()
+}
+
+// Whether a, b and c are on one line (or not all distinct), exact
+func collinear3d[T Coord](a, b, c [3]T) bool {
+	// The cross product (b - a) x (c - a) is zero iff all three projections are collinear
+	for _, axes := range [3][2]int{{0, 1}, {1, 2}, {0, 2}} {
+		i, j := axes[0], axes[1]
+		if orient([2]T{a[i], a[j]}, [2]T{b[i], b[j]}, [2]T{c[i], c[j]}) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,600 @@
+package hull
+
+import (
+	"math"
+	"math/big"
+)
+
+/******************************
+ * d-dimensional orientation  *
+ ******************************
+Orientation of a point against the hyperplane through d points in d dimensions: the sign of the
+determinant of their differences. 2 and 3 dimensions use orient and orient3d. Larger determinants
+have no simple error bound, so the filter runs Gaussian elimination in interval arithmetic instead.
+Every bound is rounded outwards by the exact error of the operation (TwoSum for sums, FMA for
+products), so exact results stay exact and a zero determinant of small coordinates is found without
+leaving float64. If the determinant's interval contains 0 anyway the sign is found in exact rational
+arithmetic. Hull faces are tested against many points, so a hyperplane keeps the cofactors of its
+determinant and each test is only a dot product.
+*/
+
+// Closed interval of float64 values
+type interval struct {
+	lo, hi float64
+}
+
+// Interval from lo to hi widened by one ulp on each side
+func round_out(lo, hi float64) interval {
+	return interval{-next_up(-lo), next_up(hi)}
+}
+
+// Smallest float64 above x, math.Nextafter(x, +Inf) without its general case
+func next_up(x float64) float64 {
+	switch {
+	case x != x || x > math.MaxFloat64:
+		return x
+	case x == 0:
+		return math.SmallestNonzeroFloat64
+	case x > 0:
+		return math.Float64frombits(math.Float64bits(x) + 1)
+	default:
+		return math.Float64frombits(math.Float64bits(x) - 1)
+	}
+}
+
+// Lower and upper bound of x, the rounded result of an operation whose exact result is x + err.
+// err is NaN if it isn't known (the operation overflowed or underflowed).
+func round_down(x, err float64) float64 {
+	if !(err >= 0) {
+		return -next_up(-x)
+	}
+	return x
+}
+
+func round_up(x, err float64) float64 {
+	if !(err <= 0) {
+		return next_up(x)
+	}
+	return x
+}
+
+// Interval holding the coordinate x
+func coord_interval[T Coord](x T) interval {
+	f := float64(x)
+	if is_int64[T]() && !(f < 0x1p63 && int64(f) == int64(x)) {
+		return round_out(f, f)
+	}
+	return interval{f, f}
+}
+
+// Interval holding x - y. int64 coordinates are subtracted first, their difference is often exact in
+// float64 when they aren't.
+func diff_interval[T Coord](x, y T) interval {
+	if is_int64[T]() {
+		a, b := int64(x), int64(y)
+		if diff := a - b; (a >= 0) == (b >= 0) || (diff >= 0) == (a >= 0) {
+			return coord_interval(diff)
+		}
+	}
+	return coord_interval(x).sub(coord_interval(y))
+}
+
+func (a interval) add(b interval) interval {
+	lo, lo_err := two_sum(a.lo, b.lo)
+	hi, hi_err := two_sum(a.hi, b.hi)
+	return interval{round_down(lo, lo_err), round_up(hi, hi_err)}
+}
+
+func (a interval) neg() interval {
+	return interval{-a.hi, -a.lo}
+}
+
+func (a interval) sub(b interval) interval {
+	return a.add(b.neg())
+}
+
+func (a interval) mul(b interval) interval {
+	// The bounds come from two of the corner products unless both intervals contain 0
+	switch {
+	case a.lo >= 0 && b.lo >= 0:
+		return interval{product_down(a.lo, b.lo), product_up(a.hi, b.hi)}
+	case a.lo >= 0 && b.hi <= 0:
+		return interval{product_down(a.hi, b.lo), product_up(a.lo, b.hi)}
+	case a.lo >= 0:
+		return interval{product_down(a.hi, b.lo), product_up(a.hi, b.hi)}
+	case a.hi <= 0 && b.lo >= 0:
+		return interval{product_down(a.lo, b.hi), product_up(a.hi, b.lo)}
+	case a.hi <= 0 && b.hi <= 0:
+		return interval{product_down(a.hi, b.hi), product_up(a.lo, b.lo)}
+	case a.hi <= 0:
+		return interval{product_down(a.lo, b.hi), product_up(a.lo, b.lo)}
+	case b.lo >= 0:
+		return interval{product_down(a.lo, b.hi), product_up(a.hi, b.hi)}
+	case b.hi <= 0:
+		return interval{product_down(a.hi, b.lo), product_up(a.lo, b.lo)}
+	}
+	// Both contain 0, or NaN which min and max pass on
+	return interval{
+		min(product_down(a.lo, b.hi), product_down(a.hi, b.lo)),
+		max(product_up(a.lo, b.lo), product_up(a.hi, b.hi)),
+	}
+}
+
+// Bounds of x * y
+func product_down(x, y float64) float64 {
+	p, err := product_error(x, y)
+	return round_down(p, err)
+}
+
+func product_up(x, y float64) float64 {
+	p, err := product_error(x, y)
+	return round_up(p, err)
+}
+
+// x * y and its rounding error, NaN if that isn't exact
+func product_error(x, y float64) (float64, float64) {
+	p, err := two_product(x, y)
+	if !exact_product(x, y, p) {
+		err = math.NaN()
+	}
+	return p, err
+}
+
+// a / b, b must not contain 0
+func (a interval) div(b interval) interval {
+	switch {
+	case b.lo > 0 && a.lo >= 0:
+		return interval{quotient_down(a.lo, b.hi), quotient_up(a.hi, b.lo)}
+	case b.lo > 0 && a.hi <= 0:
+		return interval{quotient_down(a.lo, b.lo), quotient_up(a.hi, b.hi)}
+	case b.lo > 0:
+		return interval{quotient_down(a.lo, b.lo), quotient_up(a.hi, b.lo)}
+	case b.hi < 0 && a.lo >= 0:
+		return interval{quotient_down(a.hi, b.hi), quotient_up(a.lo, b.lo)}
+	case b.hi < 0 && a.hi <= 0:
+		return interval{quotient_down(a.hi, b.lo), quotient_up(a.lo, b.hi)}
+	case b.hi < 0:
+		return interval{quotient_down(a.hi, b.hi), quotient_up(a.lo, b.hi)}
+	}
+	// NaN
+	return interval{math.NaN(), math.NaN()}
+}
+
+// Bounds of x / y
+func quotient_down(x, y float64) float64 {
+	q, err := two_quotient(x, y)
+	return round_down(q, err)
+}
+
+func quotient_up(x, y float64) float64 {
+	q, err := two_quotient(x, y)
+	return round_up(q, err)
+}
+
+// x / y and the sign of its rounding error, from the exact remainder x - q*y. NaN if the remainder
+// may not be exact.
+func two_quotient(x, y float64) (float64, float64) {
+	q := x / y
+	if x == 0 {
+		return q, 0
+	}
+	if !exact_product(q, y, x) || !exact_product(q, y, q) {
+		return q, math.NaN()
+	}
+	remainder := -math.FMA(q, y, -x)
+	if y < 0 {
+		remainder = -remainder
+	}
+	return q, remainder
+}
+
+// Whether every value of a has the same nonzero sign (false if a is NaN)
+func (a interval) nonzero() bool {
+	return a.lo > 0 || a.hi < 0
+}
+
+// Orientation of p relative to the hyperplane through the d points of simplex, in d dimensions: the
+// sign of det[simplex[1]-simplex[0], ..., simplex[d-1]-simplex[0], p-simplex[0]]. Exact.
+func orientd[T Coord](simplex [][]T, p []T) int {
+	switch len(p) {
+	case 2:
+		a, b := simplex[0], simplex[1]
+		return orient([2]T{a[0], a[1]}, [2]T{b[0], b[1]}, [2]T{p[0], p[1]})
+	case 3:
+		a, b, c := simplex[0], simplex[1], simplex[2]
+		return orient3d([3]T{a[0], a[1], a[2]}, [3]T{b[0], b[1], b[2]}, [3]T{c[0], c[1], c[2]}, [3]T{p[0], p[1], p[2]})
+	}
+	if s, ok := orientd_filter(simplex, p); ok {
+		return s
+	}
+	return orientd_exact(simplex, p)
+}
+
+// Sign of the orientation determinant in interval arithmetic, false if the interval can't decide
+func orientd_filter[T Coord](simplex [][]T, p []T) (int, bool) {
+	d := len(p)
+	var m [MaxDimension][MaxDimension]interval
+	for i := 0; i < d; i++ {
+		row := p
+		if i < d-1 {
+			row = simplex[i+1]
+		}
+		for j := 0; j < d; j++ {
+			m[i][j] = diff_interval(row[j], simplex[0][j])
+		}
+	}
+	det, ok := det_interval(&m, d)
+	if !ok || !det.nonzero() {
+		return 0, false
+	}
+	if det.lo > 0 {
+		return 1, true
+	}
+	return -1, true
+}
+
+// Interval holding the determinant of the leading d x d block of m, by fraction-free (Bareiss)
+// elimination with full pivoting, which keeps determinants of small integers exact. False if no
+// pivot excludes 0. m is overwritten.
+func det_interval(m *[MaxDimension][MaxDimension]interval, d int) (interval, bool) {
+	negate := false
+	prev := interval{1, 1}
+	for c := 0; c < d; c++ {
+		status := pivot(m, c, d, d, nil)
+		if status == pivot_zero {
+			return interval{}, true
+		}
+		if status == pivot_unknown {
+			return interval{}, false
+		}
+		if status == pivot_swapped {
+			negate = !negate
+		}
+		bareiss_step(m, c, d, d, prev)
+		prev = m[c][c]
+	}
+	if negate {
+		return prev.neg(), true
+	}
+	return prev, true
+}
+
+// Outcome of choosing a pivot
+const (
+	// The pivot is in place
+	pivot_kept = iota
+	// The pivot was moved in place by an odd number of row and column swaps
+	pivot_swapped
+	// Every remaining entry is exactly 0
+	pivot_zero
+	// No remaining entry is known to be nonzero
+	pivot_unknown
+)
+
+// Move the entry of rows c.. and columns c.. of m whose interval is farthest from 0 to m[c][c],
+// permuting perm along with the columns if not nil
+func pivot(m *[MaxDimension][MaxDimension]interval, c, rows, cols int, perm *[MaxDimension]int) int {
+	pr, pc, mag := -1, -1, 0.0
+	zero := true
+	for r := c; r < rows; r++ {
+		for j := c; j < cols; j++ {
+			x := m[r][j]
+			if x.lo != 0 || x.hi != 0 {
+				zero = false
+			}
+			if !x.nonzero() {
+				continue
+			}
+			if dist := min(math.Abs(x.lo), math.Abs(x.hi)); pr == -1 || dist > mag {
+				pr, pc, mag = r, j, dist
+			}
+		}
+	}
+	if pr == -1 {
+		if zero {
+			return pivot_zero
+		}
+		return pivot_unknown
+	}
+	swaps := 0
+	if pr != c {
+		m[c], m[pr] = m[pr], m[c]
+		swaps++
+	}
+	if pc != c {
+		for r := range m {
+			m[r][c], m[r][pc] = m[r][pc], m[r][c]
+		}
+		if perm != nil {
+			perm[c], perm[pc] = perm[pc], perm[c]
+		}
+		swaps++
+	}
+	if swaps%2 == 1 {
+		return pivot_swapped
+	}
+	return pivot_kept
+}
+
+// Eliminate column c from rows c+1.. of m with pivot m[c][c], prev is the previous pivot. Every
+// entry stays a minor of the original matrix, so the divisions are exact in exact arithmetic.
+func bareiss_step(m *[MaxDimension][MaxDimension]interval, c, rows, cols int, prev interval) {
+	for r := c + 1; r < rows; r++ {
+		for j := c + 1; j < cols; j++ {
+			m[r][j] = bareiss_update(m[r][j], m[r][c], m[c][c], m[c][j], prev)
+		}
+	}
+}
+
+// (x*pivot - below*right) / prev
+func bareiss_update(x, below, pivot, right, prev interval) interval {
+	return x.mul(pivot).sub(below.mul(right)).div(prev)
+}
+
+// Hyperplane through the d points of simplex, with the cofactors of the orientation determinant's
+// last row in interval arithmetic. The orientation of a point is then their dot product with its
+// difference from simplex[0], O(d) instead of an elimination for every point. Most points are far
+// enough from the hyperplane for the midpoints of the cofactors in plain float64.
+type hyperplane[T Coord] struct {
+	simplex  [][]T
+	cofactor [MaxDimension]interval
+	// Midpoint and radius of each cofactor
+	mid, rad [MaxDimension]float64
+}
+
+func new_hyperplane[T Coord](simplex [][]T) hyperplane[T] {
+	d := len(simplex[0])
+	h := hyperplane[T]{simplex: simplex}
+	var m [MaxDimension][MaxDimension]interval
+	for i := 0; i < d-1; i++ {
+		for j := 0; j < d; j++ {
+			m[i][j] = diff_interval(simplex[i+1][j], simplex[0][j])
+		}
+	}
+
+	// Eliminate the d-1 rows, then the cofactor for column k is the determinant with unit vector k
+	// as the last row: the final Bareiss pivot of that row after the same steps
+	var perm [MaxDimension]int
+	for j := range perm {
+		perm[j] = j
+	}
+	var pivots [MaxDimension]interval
+	negate := false
+	prev := interval{1, 1}
+	for c := 0; c < d-1; c++ {
+		switch pivot(&m, c, d-1, d, &perm) {
+		case pivot_zero:
+			// The points are affinely dependent, every cofactor is 0
+			return h
+		case pivot_unknown:
+			return hyperplane_exact(simplex)
+		case pivot_swapped:
+			negate = !negate
+		}
+		bareiss_step(&m, c, d-1, d, prev)
+		pivots[c] = prev
+		prev = m[c][c]
+	}
+	for k := 0; k < d; k++ {
+		var x [MaxDimension]interval
+		for j := 0; j < d; j++ {
+			if perm[j] == k {
+				x[j] = interval{1, 1}
+			}
+		}
+		for c := 0; c < d-1; c++ {
+			for j := c + 1; j < d; j++ {
+				x[j] = bareiss_update(x[j], x[c], m[c][c], m[c][j], pivots[c])
+			}
+		}
+		h.cofactor[k] = x[d-1]
+		if negate {
+			h.cofactor[k] = h.cofactor[k].neg()
+		}
+	}
+	h.set_midpoints()
+	return h
+}
+
+// Fill in the midpoints and radii of the cofactors. Radii are rounded up, infinite or NaN cofactors
+// give infinite or NaN radii that disable the float64 test.
+func (h *hyperplane[T]) set_midpoints() {
+	for k := range h.cofactor {
+		c := h.cofactor[k]
+		h.mid[k] = c.lo/2 + c.hi/2
+		h.rad[k] = next_up(max(h.mid[k]-c.lo, c.hi-h.mid[k]))
+	}
+}
+
+// Hyperplane with every cofactor computed exactly from its minor and then rounded outwards to an
+// interval, for when interval elimination runs out of pivots
+func hyperplane_exact[T Coord](simplex [][]T) hyperplane[T] {
+	d := len(simplex[0])
+	h := hyperplane[T]{simplex: simplex}
+	for k := 0; k < d; k++ {
+		h.cofactor[k] = minor_exact(simplex, k)
+		if (d-1+k)%2 == 1 {
+			h.cofactor[k] = h.cofactor[k].neg()
+		}
+	}
+	h.set_midpoints()
+	return h
+}
+
+// Minor of the orientation determinant without the last row and column k, exactly and then rounded
+// outwards to an interval
+func minor_exact[T Coord](simplex [][]T, k int) interval {
+	m := make([][]*big.Rat, 0, len(simplex)-1)
+	for _, q := range simplex[1:] {
+		row := rat_diff(q, simplex[0])
+		m = append(m, append(row[:k], row[k+1:]...))
+	}
+	_, det := rat_elimination(m)
+	f, exact := det.Float64()
+	if exact {
+		return interval{f, f}
+	}
+	return round_out(f, f)
+}
+
+// Orientation of p relative to the hyperplane, same as orientd(h.simplex, p)
+func (h *hyperplane[T]) orient(p []T) int {
+	if !is_int64[T]() {
+		// Differences are off by at most epsilon relative, the midpoints by the radii, and the sum of d
+		// products by (d+1)*epsilon of the sum of their magnitudes. The bound is doubled for the
+		// rounding of its own computation and padded for underflow.
+		sum, magnitude, radius := 0.0, 0.0, 0.0
+		for k := range p {
+			diff := float64(p[k]) - float64(h.simplex[0][k])
+			sum += h.mid[k] * diff
+			magnitude += math.Abs(h.mid[k] * diff)
+			radius += h.rad[k] * math.Abs(diff)
+		}
+		bound := 2*(radius+float64(len(p)+3)*epsilon*magnitude) + 0x1p-1000
+		if sum > bound {
+			return 1
+		}
+		if sum < -bound {
+			return -1
+		}
+	}
+	sum := interval{}
+	for k := range p {
+		diff := diff_interval(p[k], h.simplex[0][k])
+		sum = sum.add(h.cofactor[k].mul(diff))
+	}
+	if sum.lo > 0 {
+		return 1
+	}
+	if sum.hi < 0 {
+		return -1
+	}
+	if sum.lo == 0 && sum.hi == 0 {
+		return 0
+	}
+	return orientd(h.simplex, p)
+}
+
+// Exact coordinate differences p - q
+func rat_diff[T Coord](p, q []T) []*big.Rat {
+	row := make([]*big.Rat, len(p))
+	for j := range p {
+		row[j] = new(big.Rat).Sub(coord_rat(p[j]), coord_rat(q[j]))
+	}
+	return row
+}
+
+func coord_rat[T Coord](x T) *big.Rat {
+	if is_float[T]() {
+		return new(big.Rat).SetFloat64(float64(x))
+	}
+	return new(big.Rat).SetInt64(int64(x))
+}
+
+// Orientation determinant in exact rational arithmetic
+func orientd_exact[T Coord](simplex [][]T, p []T) int {
+	m := make([][]*big.Rat, 0, len(p))
+	for _, q := range simplex[1:] {
+		m = append(m, rat_diff(q, simplex[0]))
+	}
+	m = append(m, rat_diff(p, simplex[0]))
+	_, det := rat_elimination(m)
+	return det.Sign()
+}
+
+// Whether points are affinely independent (their differences from the first are linearly
+// independent), exact
+func affinely_independent[T Coord](points [][]T) bool {
+	m := make([][]*big.Rat, 0, len(points)-1)
+	for _, q := range points[1:] {
+		m = append(m, rat_diff(q, points[0]))
+	}
+	rank, _ := rat_elimination(m)
+	return rank == len(m)
+}
+
+// Gaussian elimination of m in place. Returns the rank of its rows and, if m is square, its
+// determinant (0 otherwise).
+func rat_elimination(m [][]*big.Rat) (rank int, det *big.Rat) {
+	det = big.NewRat(1, 1)
+	if len(m) == 0 {
+		return 0, det
+	}
+	tmp := new(big.Rat)
+	for c := 0; c < len(m[0]) && rank < len(m); c++ {
+		pivot := -1
+		for r := rank; r < len(m); r++ {
+			if m[r][c].Sign() != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot == -1 {
+			continue
+		}
+		if pivot != rank {
+			m[rank], m[pivot] = m[pivot], m[rank]
+			det.Neg(det)
+		}
+		det.Mul(det, m[rank][c])
+		for r := rank + 1; r < len(m); r++ {
+			if m[r][c].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Rat).Quo(m[r][c], m[rank][c])
+			for j := c + 1; j < len(m[r]); j++ {
+				m[r][j].Sub(m[r][j], tmp.Mul(factor, m[rank][j]))
+			}
+		}
+		rank++
+	}
+	if rank < len(m) || len(m) != len(m[0]) {
+		det.SetInt64(0)
+	}
+	return rank, det
+}
+
+// Approximate orientation determinant in float64, for picking points far from a hyperplane
+func orientd_approx[T Coord](simplex [][]T, p []T) float64 {
+	d := len(p)
+	var m [MaxDimension][MaxDimension]float64
+	for i := 0; i < d; i++ {
+		row := p
+		if i < d-1 {
+			row = simplex[i+1]
+		}
+		for j := 0; j < d; j++ {
+			m[i][j] = float64(row[j]) - float64(simplex[0][j])
+		}
+	}
+	return det_approx(&m, d)
+}
+
+// Determinant of the leading d x d block of m by Gaussian elimination with partial pivoting, m is
+// overwritten
+func det_approx(m *[MaxDimension][MaxDimension]float64, d int) float64 {
+	det := 1.0
+	for c := 0; c < d; c++ {
+		pivot := c
+		for r := c + 1; r < d; r++ {
+			if math.Abs(m[r][c]) > math.Abs(m[pivot][c]) {
+				pivot = r
+			}
+		}
+		if m[pivot][c] == 0 {
+			return 0
+		}
+		if pivot != c {
+			m[c], m[pivot] = m[pivot], m[c]
+			det = -det
+		}
+		det *= m[c][c]
+		for r := c + 1; r < d; r++ {
+			factor := m[r][c] / m[c][c]
+			for j := c + 1; j < d; j++ {
+				m[r][j] -= factor * m[c][j]
+			}
+		}
+	}
+	return det
+}
@@ -0,0 +1,172 @@
+package hull
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+/******************************
+ * Akl–Toussaint prefilter    *
+ ******************************
+The extreme points in a few directions are hull vertices, so every point strictly inside their
+polygon is strictly inside the hull and can be thrown away before any algorithm runs. On uniform
+inputs the polygon covers most of the bounding box and almost every point goes.
+
+The polygon is rebuilt as the exact hull of its candidate vertices, so the diagonal extremes (x+y
+and x-y, which overflow integer types) only need to be found approximately in float64.
+*/
+
+// Prefilter selects the polygon used to discard interior points before running an algorithm
+type Prefilter int
+
+const (
+	// Keep every point
+	PrefilterNone Prefilter = iota
+	// Quadrilateral of the leftmost, lowest, rightmost and highest points
+	PrefilterQuad
+	// Octagon of the quadrilateral points and the extremes of x+y and x-y
+	PrefilterOctagon
+)
+
+func (f Prefilter) String() string {
+	switch f {
+	case PrefilterNone:
+		return "none"
+	case PrefilterQuad:
+		return "quad"
+	case PrefilterOctagon:
+		return "octagon"
+	}
+	return fmt.Sprintf("Prefilter(%d)", int(f))
+}
+
+// Stats reports on a hull run, filled in if Options.Stats is set
+type Stats struct {
+	// Points discarded by Options.Prefilter
+	Eliminated int
+}
+
+// Candidate polygon vertices of points, the extremes for the directions filter uses
+func extreme_points[T Coord](points [][2]T, filter Prefilter) [][2]T {
+	extremes := [][2]T{
+		points[leftmost(points)], points[lowest(points)],
+		points[rightmost(points)], points[highest(points)],
+	}
+	if filter != PrefilterOctagon {
+		return extremes
+	}
+
+	// min and max of x+y and x-y
+	var diagonals [4][2]T
+	var best [4]float64
+	for i, p := range points {
+		x, y := float64(p[0]), float64(p[1])
+		keys := [4]float64{-(x + y), x - y, x + y, y - x}
+		for d, key := range keys {
+			if i == 0 || key > best[d] {
+				best[d], diagonals[d] = key, p
+			}
+		}
+	}
+	return append(extremes, diagonals[:]...)
+}
+
+// Counterclockwise, strictly convex polygon of the candidate vertices (fewer than 3 if degenerate)
+func filter_polygon[T Coord](candidates [][2]T) [][2]T {
+	insertion_sort(candidates, lex_less[T])
+	unique := candidates[:1]
+	for _, p := range candidates[1:] {
+		if p != unique[len(unique)-1] {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+	return sorted_monotone_chain(context.Background(), unique)
+}
+
+// Whether p is strictly inside the counterclockwise, strictly convex polygon
+func strictly_inside[T Coord](polygon [][2]T, p [2]T) bool {
+	for i := range polygon {
+		if orient(polygon[i], polygon[(i+1)%len(polygon)], p) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AklToussaint returns the points of points that are not strictly inside the polygon of extreme
+// points selected by filter, and how many were discarded. The hull of the survivors is the hull of
+// points, including its collinear boundary points. Chunks of points are filtered in parallel and
+// the survivors keep their input order.
+func AklToussaint[T Coord](ctx context.Context, points [][2]T, filter Prefilter) (survivors [][2]T, eliminated int, err error) {
+	if filter != PrefilterNone && filter != PrefilterQuad && filter != PrefilterOctagon {
+		return nil, 0, fmt.Errorf("hull: unknown prefilter %v", filter)
+	}
+	if len(points) == 0 {
+		return [][2]T{}, 0, nil
+	}
+	if filter == PrefilterNone || len(points) < 4 {
+		return points, 0, nil
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	chunk_size := (len(points) + workers - 1) / workers
+	chunks := make([][][2]T, 0, workers)
+	for start := 0; start < len(points); start += chunk_size {
+		chunks = append(chunks, points[start:min(start+chunk_size, len(points))])
+	}
+
+	// Extremes of each chunk, then of all of them
+	candidates := make([][][2]T, len(chunks))
+	wg := sync.WaitGroup{}
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk [][2]T) {
+			defer wg.Done()
+			candidates[i] = extreme_points(chunk, filter)
+		}(i, chunk)
+	}
+	wg.Wait()
+	var all_candidates [][2]T
+	for _, c := range candidates {
+		all_candidates = append(all_candidates, c...)
+	}
+	polygon := filter_polygon(extreme_points(all_candidates, filter))
+	if len(polygon) < 3 {
+		return points, 0, nil
+	}
+
+	kept := make([][][2]T, len(chunks))
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk [][2]T) {
+			defer wg.Done()
+			for j, p := range chunk {
+				if poll(ctx, j) {
+					return
+				}
+				if !strictly_inside(polygon, p) {
+					kept[i] = append(kept[i], p)
+				}
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	n_kept := 0
+	for _, k := range kept {
+		n_kept += len(k)
+	}
+	survivors = make([][2]T, 0, n_kept)
+	for _, k := range kept {
+		survivors = append(survivors, k...)
+	}
+	return survivors, len(points) - n_kept, nil
+}
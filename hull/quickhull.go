@@ -0,0 +1,210 @@
+package hull
+
+import (
+	"context"
+	"sync"
+)
+
+// Hull points found by a single Quickhull run, so concurrent runs don't share state
+type hull_set[T Coord] struct {
+	lock   sync.Mutex
+	points map[[2]T]bool
+}
+
+// Add the endpoints of a hull edge
+func (set *hull_set[T]) add(min_pt [2]T, max_pt [2]T) {
+	set.lock.Lock()
+	set.points[min_pt] = true
+	set.points[max_pt] = true
+	set.lock.Unlock()
+}
+
+// Points in the set, in no particular order
+func (set *hull_set[T]) slice() [][2]T {
+	hull_res := make([][2]T, 0, len(set.points))
+	for key := range set.points {
+		hull_res = append(hull_res, key)
+	}
+	return hull_res
+}
+
+func getMaxMinPt[T Coord](points [][2]T) [2]int {
+
+	max_pt_ind := -1
+	min_pt_ind := -1
+
+	for i := 0; i < len(points); i++ {
+		pt := points[i]
+		if max_pt_ind == -1 || pt[0] > points[max_pt_ind][0] || (pt[0] == points[max_pt_ind][0] && pt[1] > points[max_pt_ind][1]) {
+			max_pt_ind = i
+		}
+
+		if min_pt_ind == -1 || pt[0] < points[min_pt_ind][0] || (pt[0] == points[min_pt_ind][0] && pt[1] < points[min_pt_ind][1]) {
+			min_pt_ind = i
+		}
+	}
+
+	var res [2]int
+	res[0] = max_pt_ind
+	res[1] = min_pt_ind
+
+	return res
+}
+
+func is_above[T Coord](l1 [2]T, l2 [2]T, p [2]T) float64 {
+	AB := []float64{float64(l2[0]) - float64(l1[0]), float64(l2[1]) - float64(l1[1])}
+	AX := []float64{float64(p[0]) - float64(l1[0]), float64(p[1]) - float64(l1[1])}
+	cross := AB[0]*AX[1] - AB[1]*AX[0]
+	return cross
+}
+
+// Whether pt is strictly further than cur from the line l1->l2 on the given side, ties going to
+// the point further along l1->l2 so the chosen point is always a hull vertex
+func further_from_line[T Coord](l1 [2]T, l2 [2]T, cur [2]T, pt [2]T, side int) bool {
+	turn := side * cross_sign(l1, l2, cur, pt)
+	return turn > 0 || (turn == 0 && along(l1, l2, cur, pt) > 0)
+}
+
+func getSide[T Coord](l1 [2]T, l2 [2]T, p [2]T) int {
+	return orient(l1, l2, p)
+}
+
+func hull[T Coord](ctx context.Context, convex_hull *hull_set[T], points [][2]T, min_pt [2]T, max_pt [2]T, side int) {
+	if done(ctx) {
+		return
+	}
+	ind := -1
+
+	new_points := make([][2]T, 0)
+
+	for i := 0; i < len(points); i++ {
+		if poll(ctx, i) {
+			return
+		}
+		pt := points[i]
+
+		correct_side := getSide(min_pt, max_pt, pt) == side
+		if correct_side && (ind == -1 || further_from_line(min_pt, max_pt, points[ind], pt, side)) {
+			ind = i
+		}
+
+		if correct_side {
+			new_points = append(new_points, pt)
+		}
+	}
+
+	if ind == -1 {
+		//Add max, min
+		convex_hull.add(min_pt, max_pt)
+	} else {
+		hull(ctx, convex_hull, new_points, points[ind], min_pt, -getSide(points[ind], min_pt, max_pt))
+		hull(ctx, convex_hull, new_points, points[ind], max_pt, -getSide(points[ind], max_pt, min_pt))
+	}
+}
+
+func quickhull[T Coord](ctx context.Context, convex_hull *hull_set[T], points [][2]T) {
+	res := getMaxMinPt(points)
+
+	var min_pt [2]T
+	var max_pt [2]T
+
+	min_pt[0] = points[res[0]][0]
+	min_pt[1] = points[res[0]][1]
+
+	max_pt[0] = points[res[1]][0]
+	max_pt[1] = points[res[1]][1]
+
+	hull(ctx, convex_hull, points, max_pt, min_pt, 1)
+	hull(ctx, convex_hull, points, max_pt, min_pt, -1)
+}
+
+// Sends on c once it and every goroutine it started are finished
+func hull_p[T Coord](ctx context.Context, convex_hull *hull_set[T], points [][2]T, min_pt [2]T, max_pt [2]T, side int, c chan int) {
+	if done(ctx) {
+		c <- 1
+		return
+	}
+	ind := -1
+
+	new_points := make([][2]T, 0)
+
+	for i := 0; i < len(points); i++ {
+		if poll(ctx, i) {
+			c <- 1
+			return
+		}
+		pt := points[i]
+
+		correct_side := getSide(min_pt, max_pt, pt) == side
+		if correct_side && (ind == -1 || further_from_line(min_pt, max_pt, points[ind], pt, side)) {
+			ind = i
+		}
+
+		if correct_side {
+			new_points = append(new_points, pt)
+		}
+	}
+
+	if ind == -1 {
+		//Add max, min
+		convex_hull.add(min_pt, max_pt)
+		c <- 1
+	} else {
+
+		leftChan := make(chan int, 1)
+		rightChan := make(chan int, 1)
+
+		go hull_p(ctx, convex_hull, new_points, points[ind], min_pt, -getSide(points[ind], min_pt, max_pt), leftChan)
+		go hull_p(ctx, convex_hull, new_points, points[ind], max_pt, -getSide(points[ind], max_pt, min_pt), rightChan)
+
+		_ = <-leftChan
+		_ = <-rightChan
+		c <- 1
+	}
+}
+
+func quickhull_p[T Coord](ctx context.Context, convex_hull *hull_set[T], points [][2]T) {
+	res := getMaxMinPt(points)
+
+	var min_pt [2]T
+	var max_pt [2]T
+
+	min_pt[0] = points[res[0]][0]
+	min_pt[1] = points[res[0]][1]
+
+	max_pt[0] = points[res[1]][0]
+	max_pt[1] = points[res[1]][1]
+
+	leftChan := make(chan int, 1)
+	rightChan := make(chan int, 1)
+
+	go hull_p(ctx, convex_hull, points, max_pt, min_pt, 1, leftChan)
+	go hull_p(ctx, convex_hull, points, max_pt, min_pt, -1, rightChan)
+
+	_ = <-leftChan
+	_ = <-rightChan
+}
+
+func quickhull_serial[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+
+	convex_hull := &hull_set[T]{points: make(map[[2]T]bool)}
+
+	quickhull(ctx, convex_hull, points)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return convex_hull.slice(), nil
+}
+
+func quickhull_parallel[T Coord](ctx context.Context, points [][2]T) ([][2]T, error) {
+	convex_hull := &hull_set[T]{points: make(map[[2]T]bool)}
+
+	// Waits for every goroutine, cancelled ones return right away
+	quickhull_p(ctx, convex_hull, points)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return convex_hull.slice(), nil
+}
@@ -0,0 +1,581 @@
+package hull
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+/**********************
+ * 3D Quickhull       *
+ **********************
+Quickhull in three dimensions (Barber, Dobkin and Huhdanpaa). Starting from a tetrahedron of
+extreme points, every point outside the hull is kept in the conflict list of one face it is strictly
+above. A face with a non-empty list is expanded by its farthest point: the faces that point sees are
+removed and the horizon (the loop of edges between seen and unseen faces) is joined to the point by a
+cone of new triangles. The points of the removed faces are handed to the new faces or dropped if they
+are now inside.
+
+Faces are only ever seen when the point is strictly above them, with exact orientation tests. A
+point can still become a vertex before later points leave it on a flat part of the boundary, so the
+output is built from facets (groups of coplanar triangles) with such points dropped, and every facet
+is triangulated again as a fan. The parallel variant hands out large conflict lists to the new faces in
+parallel chunks.
+*/
+
+// Conflict lists of at least this many points are distributed in parallel
+const PAR_QH3_LIMIT int = 1 << 14
+
+// ErrCoplanar is returned for 3D input that has no volume: all points are on one plane (including
+// fewer than 4 distinct points)
+var ErrCoplanar = errors.New("hull: points are coplanar, their 3D hull has no volume")
+
+// Hull3D is the convex hull of 3D points as a closed triangulated surface
+type Hull3D[T Coord] struct {
+	// Hull vertices in lexicographic order
+	Vertices [][3]T
+	// Triangles as indices into Vertices, counterclockwise seen from outside the hull so their
+	// right-handed normals point out. Each face starts from its smallest index and faces are sorted.
+	Faces [][3]int
+}
+
+// Triangle of a hull under construction
+type face3 struct {
+	// Point indices, counterclockwise seen from outside
+	v [3]int
+	// neighbor[i] is the face across edge v[i] -> v[i+1]
+	neighbor [3]*face3
+	// Points strictly above the face and assigned to it
+	outside []int
+	visible bool
+	dead    bool
+	// Facet of the finished hull the face belongs to, -1 until known
+	facet int
+}
+
+// State of one 3D Quickhull run
+type qh3[T Coord] struct {
+	ctx      context.Context
+	points   [][3]T
+	parallel bool
+	faces    []*face3
+}
+
+func lex_less3d[T Coord](a, b [3]T) bool {
+	return compare3d(a, b) < 0
+}
+
+// Lexicographic comparison of triples, -1, 0 or 1
+func compare3d[T Coord | int](a, b [3]T) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Whether point p is strictly above face f
+func (q *qh3[T]) above(f *face3, p int) bool {
+	return orient3d(q.points[f.v[0]], q.points[f.v[1]], q.points[f.v[2]], q.points[p]) > 0
+}
+
+// Indices of four points spanning a tetrahedron, ErrCoplanar if there are none. The points are
+// picked far apart in float64 and then checked exactly.
+func (q *qh3[T]) initial_simplex() ([4]int, error) {
+	points := q.points
+	var simplex [4]int
+	if len(points) == 0 {
+		return simplex, ErrCoplanar
+	}
+	// Lexicographic extremes differ unless every point is the same
+	lo, hi := 0, 0
+	for i, p := range points {
+		if lex_less3d(p, points[lo]) {
+			lo = i
+		}
+		if lex_less3d(points[hi], p) {
+			hi = i
+		}
+	}
+	if points[lo] == points[hi] {
+		return simplex, ErrCoplanar
+	}
+
+	// Farthest from the line lo-hi
+	a, b := points[lo], points[hi]
+	third, err := farthest_point(q.ctx, points, q.parallel, func(p [3]T) float64 {
+		var cross [3]float64
+		for k := range cross {
+			i, j := (k+1)%3, (k+2)%3
+			cross[k] = (float64(b[i])-float64(a[i]))*(float64(p[j])-float64(a[j])) -
+				(float64(b[j])-float64(a[j]))*(float64(p[i])-float64(a[i]))
+		}
+		return cross[0]*cross[0] + cross[1]*cross[1] + cross[2]*cross[2]
+	})
+	if err != nil {
+		return simplex, err
+	}
+	if third == -1 || collinear3d(a, b, points[third]) {
+		third = -1
+		for i, p := range points {
+			if !collinear3d(a, b, p) {
+				third = i
+				break
+			}
+		}
+		if third == -1 {
+			return simplex, ErrCoplanar
+		}
+	}
+
+	// Farthest from the plane of the first three
+	c := points[third]
+	fourth, err := farthest_point(q.ctx, points, q.parallel, func(p [3]T) float64 {
+		return math.Abs(orient3d_approx(a, b, c, p))
+	})
+	if err != nil {
+		return simplex, err
+	}
+	if fourth == -1 || orient3d(a, b, c, points[fourth]) == 0 {
+		fourth = -1
+		for i, p := range points {
+			if orient3d(a, b, c, p) != 0 {
+				fourth = i
+				break
+			}
+		}
+		if fourth == -1 {
+			return simplex, ErrCoplanar
+		}
+	}
+	return [4]int{lo, hi, third, fourth}, nil
+}
+
+// Index of the first point with the largest positive key, -1 if no key is positive (or NaN). Chunks
+// of large inputs are scanned in parallel if parallel is set.
+func farthest_point[P any](ctx context.Context, points []P, parallel bool, key func(p P) float64) (int, error) {
+	scan := func(start, end int) (int, float64, error) {
+		best, best_key := -1, 0.0
+		for i := start; i < end; i++ {
+			if poll(ctx, i) {
+				return -1, 0, ctx.Err()
+			}
+			if k := key(points[i]); k > best_key {
+				best, best_key = i, k
+			}
+		}
+		return best, best_key, nil
+	}
+	if !parallel || len(points) < PAR_QH3_LIMIT {
+		best, _, err := scan(0, len(points))
+		return best, err
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	chunk_size := (len(points) + workers - 1) / workers
+	chunks := (len(points) + chunk_size - 1) / chunk_size
+	bests := make([]int, chunks)
+	keys := make([]float64, chunks)
+	errs := make([]error, chunks)
+	var wg sync.WaitGroup
+	for i := 0; i < chunks; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			bests[i], keys[i], errs[i] = scan(i*chunk_size, min((i+1)*chunk_size, len(points)))
+		}(i)
+	}
+	wg.Wait()
+	// Earlier chunks win ties, like the serial scan
+	best, best_key := -1, 0.0
+	for i := range bests {
+		if errs[i] != nil {
+			return -1, errs[i]
+		}
+		if bests[i] != -1 && keys[i] > best_key {
+			best, best_key = bests[i], keys[i]
+		}
+	}
+	return best, nil
+}
+
+// Faces of the tetrahedron simplex, oriented outwards and linked to each other
+func (q *qh3[T]) simplex_faces(simplex [4]int) []*face3 {
+	faces := make([]*face3, 4)
+	for omit := range simplex {
+		f := &face3{facet: -1}
+		k := 0
+		for i, v := range simplex {
+			if i != omit {
+				f.v[k] = v
+				k++
+			}
+		}
+		// The omitted vertex is inside, below the face
+		if q.above(f, simplex[omit]) {
+			f.v[1], f.v[2] = f.v[2], f.v[1]
+		}
+		faces[omit] = f
+	}
+	for _, f := range faces {
+		for i := range f.v {
+			a, b := f.v[i], f.v[(i+1)%3]
+			for _, g := range faces {
+				for j := range g.v {
+					if g.v[j] == b && g.v[(j+1)%3] == a {
+						f.neighbor[i] = g
+					}
+				}
+			}
+		}
+	}
+	return faces
+}
+
+// Hand each candidate point to the first of faces it is strictly above (calling add), points above
+// none are inside the hull and dropped. Large candidate lists are split into chunks tested in
+// parallel if parallel is set, and handed out in order so the result does not depend on the chunking.
+func assign_points[F any](ctx context.Context, candidates []int, faces []F, parallel bool, above func(f F, p int) bool, add func(f F, p int)) error {
+	if !parallel || len(candidates) < PAR_QH3_LIMIT {
+		for i, p := range candidates {
+			if poll(ctx, i) {
+				return ctx.Err()
+			}
+			for _, f := range faces {
+				if above(f, p) {
+					add(f, p)
+					break
+				}
+			}
+		}
+		return nil
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	chunk_size := (len(candidates) + workers - 1) / workers
+	var chunks [][]int
+	for start := 0; start < len(candidates); start += chunk_size {
+		chunks = append(chunks, candidates[start:min(start+chunk_size, len(candidates))])
+	}
+	// Index of the face each candidate goes to, -1 for none
+	results := make([][]int, len(chunks))
+	errs := make([]error, len(chunks))
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []int) {
+			defer wg.Done()
+			targets := make([]int, len(chunk))
+			for k, p := range chunk {
+				if poll(ctx, k) {
+					errs[i] = ctx.Err()
+					return
+				}
+				targets[k] = -1
+				for j, f := range faces {
+					if above(f, p) {
+						targets[k] = j
+						break
+					}
+				}
+			}
+			results[i] = targets
+		}(i, chunk)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	for i, chunk := range chunks {
+		for k, p := range chunk {
+			if target := results[i][k]; target != -1 {
+				add(faces[target], p)
+			}
+		}
+	}
+	return nil
+}
+
+// Hand candidates to faces, see assign_points
+func (q *qh3[T]) assign(candidates []int, faces []*face3) error {
+	return assign_points(q.ctx, candidates, faces, q.parallel, q.above, func(f *face3, p int) {
+		f.outside = append(f.outside, p)
+	})
+}
+
+// Point of f's conflict list farthest from its plane
+func (q *qh3[T]) farthest(f *face3) int {
+	a, b, c := q.points[f.v[0]], q.points[f.v[1]], q.points[f.v[2]]
+	best, best_dist := f.outside[0], orient3d_approx(a, b, c, q.points[f.outside[0]])
+	for _, p := range f.outside[1:] {
+		if d := orient3d_approx(a, b, c, q.points[p]); d > best_dist {
+			best, best_dist = p, d
+		}
+	}
+	return best
+}
+
+// Horizon edge: edge of a visible face whose neighbor is not visible
+type horizon_edge struct {
+	face *face3
+	edge int
+}
+
+// Faces apex is strictly above, connected to start (which it is above), and their horizon edges
+// in order around the horizon
+func (q *qh3[T]) visible_faces(start *face3, apex int) ([]*face3, []horizon_edge) {
+	start.visible = true
+	visible := []*face3{start}
+	var horizon []horizon_edge
+	// Depth first, crossing the edges of each face in order after the one it was entered by, so
+	// horizon edges are met in the order they go around
+	var walk func(f *face3, entry int)
+	walk = func(f *face3, entry int) {
+		for k := 1; k <= 3; k++ {
+			e := (entry + k) % 3
+			g := f.neighbor[e]
+			if g.visible {
+				continue
+			}
+			if !q.above(g, apex) {
+				horizon = append(horizon, horizon_edge{f, e})
+				continue
+			}
+			g.visible = true
+			visible = append(visible, g)
+			for j := range g.neighbor {
+				if g.neighbor[j] == f {
+					walk(g, j)
+					break
+				}
+			}
+		}
+	}
+	walk(start, 2)
+	return visible, horizon
+}
+
+// Add apex, which is above f, to the hull. Returns the new faces.
+func (q *qh3[T]) expand(f *face3, apex int) ([]*face3, error) {
+	visible, horizon := q.visible_faces(f, apex)
+
+	// Cone of new faces over the horizon
+	cone := make([]*face3, len(horizon))
+	for i, h := range horizon {
+		a, b := h.face.v[h.edge], h.face.v[(h.edge+1)%3]
+		// Same winding as the removed face, which apex is above
+		nf := &face3{v: [3]int{a, b, apex}, facet: -1}
+		across := h.face.neighbor[h.edge]
+		nf.neighbor[0] = across
+		for j := range across.neighbor {
+			if across.neighbor[j] == h.face {
+				across.neighbor[j] = nf
+			}
+		}
+		cone[i] = nf
+	}
+	for i, nf := range cone {
+		// Edge b -> apex is shared with the face of the next horizon edge, which starts at b
+		next := cone[(i+1)%len(cone)]
+		nf.neighbor[1] = next
+		next.neighbor[2] = nf
+	}
+	q.faces = append(q.faces, cone...)
+
+	var candidates []int
+	for _, g := range visible {
+		g.dead = true
+		for _, p := range g.outside {
+			if p != apex {
+				candidates = append(candidates, p)
+			}
+		}
+		g.outside = nil
+	}
+	if err := q.assign(candidates, cone); err != nil {
+		return nil, err
+	}
+	return cone, nil
+}
+
+// Build the hull of q.points
+func (q *qh3[T]) build() (*Hull3D[T], error) {
+	simplex, err := q.initial_simplex()
+	if err != nil {
+		return nil, err
+	}
+	q.faces = q.simplex_faces(simplex)
+	candidates := make([]int, 0, len(q.points))
+	for i := range q.points {
+		if i != simplex[0] && i != simplex[1] && i != simplex[2] && i != simplex[3] {
+			candidates = append(candidates, i)
+		}
+	}
+	if err := q.assign(candidates, q.faces); err != nil {
+		return nil, err
+	}
+
+	pending := append([]*face3(nil), q.faces...)
+	for len(pending) > 0 {
+		if done(q.ctx) {
+			return nil, q.ctx.Err()
+		}
+		f := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if f.dead || len(f.outside) == 0 {
+			continue
+		}
+		cone, err := q.expand(f, q.farthest(f))
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, cone...)
+	}
+	return q.result(), nil
+}
+
+// Facets of the hull under construction: the boundaries of maximal groups of coplanar live faces,
+// counterclockwise seen from outside and without points in the middle of their edges
+func (q *qh3[T]) facets() [][]int {
+	var facets [][]int
+	var group []*face3
+	next := make(map[int]int)
+	for _, seed := range q.faces {
+		if seed.dead || seed.facet != -1 {
+			continue
+		}
+		// Flood fill the coplanar neighbors
+		id := len(facets)
+		seed.facet = id
+		group = append(group[:0], seed)
+		for i := 0; i < len(group); i++ {
+			f := group[i]
+			a, b, c := q.points[f.v[0]], q.points[f.v[1]], q.points[f.v[2]]
+			for e, g := range f.neighbor {
+				if g.facet != -1 {
+					continue
+				}
+				// Vertex of g off the shared edge
+				opposite := g.v[0] + g.v[1] + g.v[2] - f.v[e] - f.v[(e+1)%3]
+				if orient3d(a, b, c, q.points[opposite]) == 0 {
+					g.facet = id
+					group = append(group, g)
+				}
+			}
+		}
+		if len(group) == 1 {
+			facets = append(facets, []int{seed.v[0], seed.v[1], seed.v[2]})
+			continue
+		}
+
+		// Walk the boundary edges, each boundary vertex starts exactly one
+		clear(next)
+		start := -1
+		for _, f := range group {
+			for e, g := range f.neighbor {
+				if g.facet != id {
+					next[f.v[e]] = f.v[(e+1)%3]
+					start = f.v[e]
+				}
+			}
+		}
+		loop := []int{start}
+		for v := next[start]; v != start; v = next[v] {
+			loop = append(loop, v)
+		}
+
+		// Points between two neighbors on a line are on an edge of the facet, not vertices
+		var corners []int
+		for i, v := range loop {
+			prev, succ := loop[(i+len(loop)-1)%len(loop)], loop[(i+1)%len(loop)]
+			if !collinear3d(q.points[prev], q.points[v], q.points[succ]) {
+				corners = append(corners, v)
+			}
+		}
+		facets = append(facets, corners)
+	}
+	return facets
+}
+
+// Hull in the canonical Hull3D form: the extreme points, with each facet triangulated as a fan from
+// its smallest vertex
+func (q *qh3[T]) result() *Hull3D[T] {
+	facets := q.facets()
+	var vertices []int
+	// Index of each point in the output, -1 if not a vertex
+	index := make([]int, len(q.points))
+	for i := range index {
+		index[i] = -1
+	}
+	for _, facet := range facets {
+		for _, v := range facet {
+			if index[v] == -1 {
+				index[v] = 0
+				vertices = append(vertices, v)
+			}
+		}
+	}
+	slices.SortFunc(vertices, func(a, b int) int {
+		return compare3d(q.points[a], q.points[b])
+	})
+	h := &Hull3D[T]{Vertices: make([][3]T, len(vertices))}
+	for i, v := range vertices {
+		h.Vertices[i] = q.points[v]
+		index[v] = i
+	}
+
+	for _, facet := range facets {
+		// Start the fan from the smallest index, keeping the winding
+		first := 0
+		for i, v := range facet {
+			if index[v] < index[facet[first]] {
+				first = i
+			}
+		}
+		n := len(facet)
+		for i := 1; i+1 < n; i++ {
+			h.Faces = append(h.Faces, [3]int{
+				index[facet[first]], index[facet[(first+i)%n]], index[facet[(first+i+1)%n]],
+			})
+		}
+	}
+	slices.SortFunc(h.Faces, compare3d[int])
+	return h
+}
+
+func quickhull3d_serial[T Coord](ctx context.Context, points [][3]T) (*Hull3D[T], error) {
+	q := &qh3[T]{ctx: ctx, points: points}
+	return q.build()
+}
+
+func quickhull3d_parallel[T Coord](ctx context.Context, points [][3]T) (*Hull3D[T], error) {
+	q := &qh3[T]{ctx: ctx, points: points, parallel: true}
+	return q.build()
+}
+
+// Quickhull3DSerial computes the convex hull of 3D points with sequential Quickhull. Returns
+// ErrCoplanar if the points do not span a volume.
+func Quickhull3DSerial[T Coord](ctx context.Context, points [][3]T) (*Hull3D[T], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return quickhull3d_serial(ctx, points)
+}
+
+// Quickhull3DParallel computes the convex hull of 3D points with Quickhull, distributing large
+// conflict lists to the new faces in parallel. Returns ErrCoplanar if the points do not span a volume.
+func Quickhull3DParallel[T Coord](ctx context.Context, points [][3]T) (*Hull3D[T], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return quickhull3d_parallel(ctx, points)
+}
@@ -0,0 +1,209 @@
+package hull
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Check that h is a closed hull of points: every face has every point on or below it, faces are
+// proper triangles meeting edge to edge, and the vertices are sorted, distinct input points
+func check_hull3d[T Coord](t *testing.T, points [][3]T, h *Hull3D[T]) {
+	t.Helper()
+	if !slices.IsSortedFunc(h.Vertices, compare3d[T]) || len(slices.CompactFunc(slices.Clone(h.Vertices), func(a, b [3]T) bool { return a == b })) != len(h.Vertices) {
+		t.Fatalf("vertices not sorted and distinct: %v", h.Vertices)
+	}
+	input := make(map[[3]T]bool, len(points))
+	for _, p := range points {
+		input[p] = true
+	}
+	for _, v := range h.Vertices {
+		if !input[v] {
+			t.Fatalf("vertex %v is not an input point", v)
+		}
+	}
+	if !slices.IsSortedFunc(h.Faces, compare3d[int]) {
+		t.Fatalf("faces not sorted: %v", h.Faces)
+	}
+
+	// Each directed edge once, and its reverse once
+	edges := make(map[[2]int]int)
+	used := make([]bool, len(h.Vertices))
+	for _, f := range h.Faces {
+		if f[0] > f[1] || f[0] > f[2] {
+			t.Fatalf("face %v does not start from its smallest index", f)
+		}
+		a, b, c := h.Vertices[f[0]], h.Vertices[f[1]], h.Vertices[f[2]]
+		if collinear3d(a, b, c) {
+			t.Fatalf("face %v is degenerate", f)
+		}
+		for _, p := range points {
+			if orient3d(a, b, c, p) > 0 {
+				t.Fatalf("%v is above face %v (%v %v %v)", p, f, a, b, c)
+			}
+		}
+		for i := range f {
+			edges[[2]int{f[i], f[(i+1)%3]}]++
+			used[f[i]] = true
+		}
+	}
+	for e, n := range edges {
+		if n != 1 || edges[[2]int{e[1], e[0]}] != 1 {
+			t.Fatalf("edge %v is not shared by exactly two faces", e)
+		}
+	}
+	if i := slices.Index(used, false); i != -1 {
+		t.Fatalf("vertex %v is on no face", h.Vertices[i])
+	}
+	// Euler's formula for a triangulated sphere
+	if len(h.Vertices)-len(edges)/2+len(h.Faces) != 2 {
+		t.Fatalf("V - E + F = %d - %d + %d, want 2", len(h.Vertices), len(edges)/2, len(h.Faces))
+	}
+}
+
+// Hull vertices of points in general position (no 4 coplanar), by trying every plane through 3 points
+func brute_force_vertices3d[T Coord](points [][3]T) [][3]T {
+	var vertices [][3]T
+	for i, a := range points {
+		for j, b := range points[:i] {
+			for _, c := range points[:j] {
+				above, below := false, false
+				for _, p := range points {
+					switch orient3d(a, b, c, p) {
+					case 1:
+						above = true
+					case -1:
+						below = true
+					}
+				}
+				if above != below {
+					vertices = append(vertices, a, b, c)
+				}
+			}
+		}
+	}
+	slices.SortFunc(vertices, compare3d[T])
+	return slices.Compact(vertices)
+}
+
+// Both 3D algorithms on points, which they must agree on
+func hull3d_both[T Coord](t *testing.T, points [][3]T) (*Hull3D[T], error) {
+	t.Helper()
+	serial, err := Quickhull3DSerial(context.Background(), slices.Clone(points))
+	parallel, perr := Quickhull3DParallel(context.Background(), slices.Clone(points))
+	if !errors.Is(perr, err) {
+		t.Fatalf("serial returned %v, parallel %v", err, perr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(serial.Vertices, parallel.Vertices) || !slices.Equal(serial.Faces, parallel.Faces) {
+		t.Fatalf("serial and parallel hulls differ:\n%v\n%v", serial, parallel)
+	}
+	return serial, nil
+}
+
+func TestQuickhull3DCases(t *testing.T) {
+	// Corners of the cube [0,2]^3, its center, the centers of its faces and the midpoints of its edges
+	var corners, cube [][3]int32
+	for x := int32(0); x <= 2; x++ {
+		for y := int32(0); y <= 2; y++ {
+			for z := int32(0); z <= 2; z++ {
+				if x != 1 && y != 1 && z != 1 {
+					corners = append(corners, [3]int32{x, y, z})
+				}
+				cube = append(cube, [3]int32{x, y, z})
+			}
+		}
+	}
+	tetrahedron := [][3]int32{{0, 0, 0}, {6, 0, 0}, {0, 6, 0}, {0, 0, 6}}
+	var repeated [][3]int32
+	for i := 0; i < 5; i++ {
+		repeated = append(repeated, tetrahedron...)
+		repeated = append(repeated, [3]int32{1, 1, 1}, [3]int32{2, 2, 2}, [3]int32{3, 3, 0})
+	}
+	var plane, tilted [][3]int32
+	for x := int32(0); x < 5; x++ {
+		for y := int32(0); y < 5; y++ {
+			plane = append(plane, [3]int32{x, y, 7})
+			tilted = append(tilted, [3]int32{x, y, 3 - x + 2*y})
+		}
+	}
+
+	tests := []struct {
+		name     string
+		points   [][3]int32
+		vertices [][3]int32
+		faces    int
+		err      error
+	}{
+		{"tetrahedron", tetrahedron, [][3]int32{{0, 0, 0}, {0, 0, 6}, {0, 6, 0}, {6, 0, 0}}, 4, nil},
+		{"cube with interior, face and edge points", cube, corners, 12, nil},
+		{"duplicates", repeated, [][3]int32{{0, 0, 0}, {0, 0, 6}, {0, 6, 0}, {6, 0, 0}}, 4, nil},
+		{"octahedron", [][3]int32{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}, {0, 0, 0}},
+			[][3]int32{{-1, 0, 0}, {0, -1, 0}, {0, 0, -1}, {0, 0, 1}, {0, 1, 0}, {1, 0, 0}}, 8, nil},
+		{"empty", nil, nil, 0, ErrCoplanar},
+		{"one point", [][3]int32{{1, 2, 3}, {1, 2, 3}}, nil, 0, ErrCoplanar},
+		{"collinear", [][3]int32{{0, 0, 0}, {1, 1, 1}, {2, 2, 2}, {5, 5, 5}}, nil, 0, ErrCoplanar},
+		{"plane", plane, nil, 0, ErrCoplanar},
+		{"tilted plane", tilted, nil, 0, ErrCoplanar},
+	}
+	for _, test := range tests {
+		h, err := hull3d_both(t, test.points)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("%s: got %v, want %v", test.name, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+		check_hull3d(t, test.points, h)
+		if !slices.Equal(h.Vertices, test.vertices) || len(h.Faces) != test.faces {
+			t.Fatalf("%s: got vertices %v and %d faces, want %v and %d", test.name, h.Vertices, len(h.Faces), test.vertices, test.faces)
+		}
+	}
+}
+
+func TestQuickhull3DBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 30; trial++ {
+		points := make([][3]float64, 5+r.Intn(25))
+		for i := range points {
+			points[i] = [3]float64{r.NormFloat64(), r.NormFloat64(), r.NormFloat64()}
+		}
+		h, err := hull3d_both(t, points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_hull3d(t, points, h)
+		if want := brute_force_vertices3d(points); !slices.Equal(h.Vertices, want) {
+			t.Fatalf("got vertices %v, want %v", h.Vertices, want)
+		}
+	}
+}
+
+// Enough points for the parallel variant to hand out conflict lists in parallel
+func TestQuickhull3DSerialParallel(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	normal := make([][3]float64, 4*PAR_QH3_LIMIT)
+	for i := range normal {
+		normal[i] = [3]float64{r.NormFloat64(), r.NormFloat64(), r.NormFloat64()}
+	}
+	h, err := hull3d_both(t, normal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check_hull3d(t, normal, h)
+
+	// Many coplanar and duplicate points
+	grid := make([][3]int64, 4*PAR_QH3_LIMIT)
+	for i := range grid {
+		grid[i] = [3]int64{int64(r.Intn(12)), int64(r.Intn(12)), int64(r.Intn(12))}
+	}
+	hg, err := hull3d_both(t, grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check_hull3d(t, grid, hg)
+}
@@ -0,0 +1,581 @@
+package hull
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+/****************************
+ * d-dimensional Quickhull  *
+ ****************************
+The 3D Quickhull generalized to d dimensions, for points given as coordinate slices. Faces are
+simplices of d vertices and neighbor[i] is the face across the ridge without vertex i. Expanding a
+face by its farthest point replaces every face the point sees by a cone from the point to the
+horizon ridges, and the cone faces are linked to each other through the ridges they share.
+
+The faces of the finished hull are simplices, so coplanar neighbors are merged into facets. A facet
+can hold points that were vertices for a while and ended up in the middle of it (or of one of its
+sides), so its corners are found by dropping a coordinate the facet is not parallel to and taking
+the hull of its points in d-1 dimensions.
+*/
+
+// MaxDimension is the largest dimension supported by QuickhullDSerial and QuickhullDParallel and
+// stored in binary point files
+const MaxDimension = 8
+
+// ErrDegenerate is returned for d-dimensional input that has no volume: all points lie in one
+// hyperplane (including fewer than d+1 distinct points)
+var ErrDegenerate = errors.New("hull: points lie in a hyperplane, their hull has no volume")
+
+// Facet is a facet of a d-dimensional hull
+type Facet struct {
+	// Indices of the facet's corners into the input points, ascending
+	Vertices []int
+	// Outward unit normal, and offset so that Normal·x <= Offset for every x in the hull (up to
+	// float64 rounding, the facets themselves are exact)
+	Normal []float64
+	Offset float64
+}
+
+// HullD is the convex hull of d-dimensional points
+type HullD struct {
+	Dim int
+	// Indices of the hull vertices into the input points, ascending
+	Vertices []int
+	// Facets sorted by their vertex lists
+	Facets []Facet
+}
+
+// Simplex face of a hull under construction
+type faced[T Coord] struct {
+	// Point indices, ordered so that points outside the hull have positive orientation
+	v []int
+	// Coordinates of the vertices
+	pts [][]T
+	// Hyperplane through pts for orientation tests, nil until first needed
+	plane *hyperplane[T]
+	// neighbor[i] is the face across the ridge without v[i]
+	neighbor []*faced[T]
+	// Points strictly above the face and assigned to it
+	outside []int
+	visible bool
+	dead    bool
+	// Facet of the finished hull the face belongs to, -1 until known
+	facet int
+}
+
+// Ridge of d-1 vertex indices in ascending order, padded with -1
+type ridge_key [MaxDimension]int
+
+// Cone face waiting for its neighbor across a ridge
+type ridge_ref[T Coord] struct {
+	face  *faced[T]
+	index int
+}
+
+// State of one d-dimensional Quickhull run
+type qhd[T Coord] struct {
+	ctx      context.Context
+	points   [][]T
+	dim      int
+	parallel bool
+	faces    []*faced[T]
+}
+
+func (q *qhd[T]) new_face(v []int) *faced[T] {
+	f := &faced[T]{v: v, pts: make([][]T, len(v)), neighbor: make([]*faced[T], len(v)), facet: -1}
+	for i, p := range v {
+		f.pts[i] = q.points[p]
+	}
+	return f
+}
+
+// Whether point p is strictly above face f
+func (q *qhd[T]) above(f *faced[T], p int) bool {
+	return f.hyperplane().orient(q.points[p]) > 0
+}
+
+// Hyperplane of f, computed on first use. Many faces are replaced before any point is tested
+// against them.
+func (f *faced[T]) hyperplane() *hyperplane[T] {
+	if f.plane == nil {
+		h := new_hyperplane(f.pts)
+		f.plane = &h
+	}
+	return f.plane
+}
+
+// Indices of d+1 points spanning a simplex, ErrDegenerate if there are none. Each point is picked
+// far from the span of the previous ones in float64 and then checked exactly.
+func (q *qhd[T]) initial_simplex() ([]int, error) {
+	points := q.points
+	if len(points) == 0 {
+		return nil, ErrDegenerate
+	}
+	// Lexicographic extremes differ unless every point is the same
+	lo, hi := 0, 0
+	for i, p := range points {
+		if slices.Compare(p, points[lo]) < 0 {
+			lo = i
+		}
+		if slices.Compare(points[hi], p) < 0 {
+			hi = i
+		}
+	}
+	if lo == hi || slices.Equal(points[lo], points[hi]) {
+		return nil, ErrDegenerate
+	}
+
+	simplex := []int{lo, hi}
+	origin := points[lo]
+	// Orthonormal basis of the span so far, for distances in float64
+	var basis [][]float64
+	residual := func(p []T) []float64 {
+		r := make([]float64, q.dim)
+		for j := range r {
+			r[j] = float64(p[j]) - float64(origin[j])
+		}
+		for _, e := range basis {
+			dot := 0.0
+			for j := range r {
+				dot += r[j] * e[j]
+			}
+			for j := range r {
+				r[j] -= dot * e[j]
+			}
+		}
+		return r
+	}
+	extend_basis := func(p []T) {
+		r := residual(p)
+		norm := 0.0
+		for _, x := range r {
+			norm += x * x
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 || math.IsInf(norm, 0) || math.IsNaN(norm) {
+			// Distances just get rougher, the choice is checked exactly anyway
+			return
+		}
+		for j := range r {
+			r[j] /= norm
+		}
+		basis = append(basis, r)
+	}
+	extend_basis(points[hi])
+
+	chosen := func(extra int) [][]T {
+		pts := make([][]T, 0, len(simplex)+1)
+		for _, i := range simplex {
+			pts = append(pts, points[i])
+		}
+		return append(pts, points[extra])
+	}
+	for len(simplex) <= q.dim {
+		far, err := farthest_point(q.ctx, points, q.parallel, func(p []T) float64 {
+			dist := 0.0
+			for _, x := range residual(p) {
+				dist += x * x
+			}
+			return dist
+		})
+		if err != nil {
+			return nil, err
+		}
+		if far == -1 || !affinely_independent(chosen(far)) {
+			far = -1
+			for i := range points {
+				if poll(q.ctx, i) {
+					return nil, q.ctx.Err()
+				}
+				if affinely_independent(chosen(i)) {
+					far = i
+					break
+				}
+			}
+			if far == -1 {
+				return nil, ErrDegenerate
+			}
+		}
+		simplex = append(simplex, far)
+		extend_basis(points[far])
+	}
+	return simplex, nil
+}
+
+// Faces of the initial simplex, oriented outwards and linked to each other
+func (q *qhd[T]) simplex_faces(simplex []int) []*faced[T] {
+	faces := make([]*faced[T], len(simplex))
+	for omit := range simplex {
+		v := make([]int, 0, q.dim)
+		for i, p := range simplex {
+			if i != omit {
+				v = append(v, p)
+			}
+		}
+		f := q.new_face(v)
+		// The omitted vertex is inside, below the face
+		if q.above(f, simplex[omit]) {
+			f.v[0], f.v[1] = f.v[1], f.v[0]
+			f.pts[0], f.pts[1] = f.pts[1], f.pts[0]
+			f.plane = nil
+		}
+		faces[omit] = f
+	}
+	// The faces without simplex[a] and simplex[b] meet at the ridge without both
+	for a, f := range faces {
+		for b, g := range faces {
+			if a != b {
+				f.neighbor[slices.Index(f.v, simplex[b])] = g
+			}
+		}
+	}
+	return faces
+}
+
+// Hand candidates to faces, see assign_points
+func (q *qhd[T]) assign(candidates []int, faces []*faced[T]) error {
+	if q.parallel && len(candidates) >= PAR_QH3_LIMIT {
+		// Workers must not fill in hyperplanes concurrently
+		for _, f := range faces {
+			f.hyperplane()
+		}
+	}
+	return assign_points(q.ctx, candidates, faces, q.parallel, q.above, func(f *faced[T], p int) {
+		f.outside = append(f.outside, p)
+	})
+}
+
+// Point of f's conflict list farthest from its hyperplane
+func (q *qhd[T]) farthest(f *faced[T]) int {
+	best, best_dist := f.outside[0], orientd_approx(f.pts, q.points[f.outside[0]])
+	for _, p := range f.outside[1:] {
+		if d := orientd_approx(f.pts, q.points[p]); d > best_dist {
+			best, best_dist = p, d
+		}
+	}
+	return best
+}
+
+// Ridge of face vertices v without v[skip]
+func make_ridge_key(v []int, skip int) ridge_key {
+	var key ridge_key
+	n := 0
+	for i, p := range v {
+		if i != skip {
+			key[n] = p
+			n++
+		}
+	}
+	slices.Sort(key[:n])
+	for i := n; i < len(key); i++ {
+		key[i] = -1
+	}
+	return key
+}
+
+// Add apex, which is above f, to the hull. Returns the new faces.
+func (q *qhd[T]) expand(f *faced[T], apex int) ([]*faced[T], error) {
+	// Faces apex sees, connected to f, and the ridges between them and the faces it doesn't see
+	f.visible = true
+	visible := []*faced[T]{f}
+	var horizon []ridge_ref[T]
+	for i := 0; i < len(visible); i++ {
+		g := visible[i]
+		for e, h := range g.neighbor {
+			if h.visible {
+				continue
+			}
+			if q.above(h, apex) {
+				h.visible = true
+				visible = append(visible, h)
+			} else {
+				horizon = append(horizon, ridge_ref[T]{g, e})
+			}
+		}
+	}
+
+	// Cone of new faces, each horizon ridge with apex in place of the vertex it left out. Apex takes
+	// the place of a vertex below the new face, so the orientation carries over.
+	cone := make([]*faced[T], len(horizon))
+	open_ridges := make(map[ridge_key]ridge_ref[T])
+	for i, h := range horizon {
+		v := slices.Clone(h.face.v)
+		v[h.index] = apex
+		nf := q.new_face(v)
+		across := h.face.neighbor[h.index]
+		nf.neighbor[h.index] = across
+		across.neighbor[slices.Index(across.neighbor, h.face)] = nf
+
+		// The other ridges contain apex and are shared with another cone face
+		for j := range v {
+			if j == h.index {
+				continue
+			}
+			key := make_ridge_key(v, j)
+			if other, ok := open_ridges[key]; ok {
+				nf.neighbor[j] = other.face
+				other.face.neighbor[other.index] = nf
+				delete(open_ridges, key)
+			} else {
+				open_ridges[key] = ridge_ref[T]{nf, j}
+			}
+		}
+		cone[i] = nf
+	}
+	q.faces = append(q.faces, cone...)
+
+	var candidates []int
+	for _, g := range visible {
+		g.dead = true
+		for _, p := range g.outside {
+			if p != apex {
+				candidates = append(candidates, p)
+			}
+		}
+		g.outside = nil
+	}
+	if err := q.assign(candidates, cone); err != nil {
+		return nil, err
+	}
+	return cone, nil
+}
+
+// Build the hull of q.points
+func (q *qhd[T]) build() (*HullD, error) {
+	simplex, err := q.initial_simplex()
+	if err != nil {
+		return nil, err
+	}
+	q.faces = q.simplex_faces(simplex)
+	candidates := make([]int, 0, len(q.points))
+	for i := range q.points {
+		if !slices.Contains(simplex, i) {
+			candidates = append(candidates, i)
+		}
+	}
+	if err := q.assign(candidates, q.faces); err != nil {
+		return nil, err
+	}
+
+	pending := append([]*faced[T](nil), q.faces...)
+	for len(pending) > 0 {
+		if done(q.ctx) {
+			return nil, q.ctx.Err()
+		}
+		f := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if f.dead || len(f.outside) == 0 {
+			continue
+		}
+		cone, err := q.expand(f, q.farthest(f))
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, cone...)
+	}
+	return q.result()
+}
+
+// Corners of the facet made of the coplanar faces group
+func (q *qhd[T]) facet_corners(group []*faced[T]) ([]int, error) {
+	if len(group) == 1 {
+		corners := slices.Clone(group[0].v)
+		slices.Sort(corners)
+		return corners, nil
+	}
+	var points []int
+	for _, f := range group {
+		points = append(points, f.v...)
+	}
+	slices.Sort(points)
+	points = slices.Compact(points)
+
+	// Drop a coordinate the facet is not parallel to, which maps it one to one into d-1 dimensions
+	project := func(p []T, drop int) []T {
+		return append(slices.Clone(p[:drop]), p[drop+1:]...)
+	}
+	simplex := group[0].pts
+	drop := -1
+	for k := 0; k < q.dim && drop == -1; k++ {
+		projected := make([][]T, len(simplex))
+		for i, p := range simplex {
+			projected[i] = project(p, k)
+		}
+		if q.dim == 2 {
+			if projected[0][0] != projected[1][0] {
+				drop = k
+			}
+		} else if orientd(projected[:q.dim-1], projected[q.dim-1]) != 0 {
+			drop = k
+		}
+	}
+
+	projected := make([][]T, len(points))
+	for i, p := range points {
+		projected[i] = project(q.points[p], drop)
+	}
+	if q.dim == 2 {
+		// Ends of a segment
+		lo, hi := 0, 0
+		for i, p := range projected {
+			if p[0] < projected[lo][0] {
+				lo = i
+			}
+			if p[0] > projected[hi][0] {
+				hi = i
+			}
+		}
+		return []int{min(points[lo], points[hi]), max(points[lo], points[hi])}, nil
+	}
+	sub := &qhd[T]{ctx: q.ctx, points: projected, dim: q.dim - 1}
+	h, err := sub.build()
+	if err != nil {
+		return nil, err
+	}
+	corners := make([]int, len(h.Vertices))
+	for i, v := range h.Vertices {
+		corners[i] = points[v]
+	}
+	// points is ascending, so corners is too
+	return corners, nil
+}
+
+// Outward unit normal and offset of the hyperplane of f
+func (q *qhd[T]) normal(f *faced[T]) ([]float64, float64) {
+	d := q.dim
+	// Differences scaled to at most 1, which doesn't change the direction
+	var rows [MaxDimension][MaxDimension]float64
+	scale := 0.0
+	for i := 1; i < d; i++ {
+		for j := 0; j < d; j++ {
+			rows[i-1][j] = float64(f.pts[i][j]) - float64(f.pts[0][j])
+			scale = max(scale, math.Abs(rows[i-1][j]))
+		}
+	}
+	for i := 0; i < d-1; i++ {
+		for j := 0; j < d; j++ {
+			rows[i][j] /= scale
+		}
+	}
+
+	// Component k is the orientation of the unit vector k, so the normal points to the outside
+	normal := make([]float64, d)
+	norm := 0.0
+	for k := range normal {
+		m := rows
+		for j := 0; j < d; j++ {
+			m[d-1][j] = 0
+		}
+		m[d-1][k] = 1
+		normal[k] = det_approx(&m, d)
+		norm += normal[k] * normal[k]
+	}
+	norm = math.Sqrt(norm)
+	offset := 0.0
+	for k := range normal {
+		normal[k] /= norm
+		offset += normal[k] * float64(f.pts[0][k])
+	}
+	return normal, offset
+}
+
+// Merge the live faces into facets and collect the hull
+func (q *qhd[T]) result() (*HullD, error) {
+	h := &HullD{Dim: q.dim}
+	var group []*faced[T]
+	for _, seed := range q.faces {
+		if seed.dead || seed.facet != -1 {
+			continue
+		}
+		// Flood fill the coplanar neighbors
+		id := len(h.Facets)
+		seed.facet = id
+		group = append(group[:0], seed)
+		for i := 0; i < len(group); i++ {
+			f := group[i]
+			for _, g := range f.neighbor {
+				if g.facet != -1 {
+					continue
+				}
+				// Vertex of g off the shared ridge
+				opposite := g.v[slices.Index(g.neighbor, f)]
+				if f.hyperplane().orient(q.points[opposite]) == 0 {
+					g.facet = id
+					group = append(group, g)
+				}
+			}
+		}
+
+		corners, err := q.facet_corners(group)
+		if err != nil {
+			return nil, err
+		}
+		normal, offset := q.normal(seed)
+		h.Facets = append(h.Facets, Facet{Vertices: corners, Normal: normal, Offset: offset})
+		h.Vertices = append(h.Vertices, corners...)
+	}
+	slices.Sort(h.Vertices)
+	h.Vertices = slices.Compact(h.Vertices)
+	slices.SortFunc(h.Facets, func(a, b Facet) int {
+		return slices.Compare(a.Vertices, b.Vertices)
+	})
+	return h, nil
+}
+
+// Check that every point has dim coordinates
+func check_dimension[T Coord](points [][]T) (int, error) {
+	if len(points) == 0 {
+		return 0, ErrDegenerate
+	}
+	dim := len(points[0])
+	if dim < 2 || dim > MaxDimension {
+		return 0, fmt.Errorf("hull: unsupported dimension %d", dim)
+	}
+	for i, p := range points {
+		if len(p) != dim {
+			return 0, fmt.Errorf("hull: point %d has %d coordinates, expected %d", i, len(p), dim)
+		}
+	}
+	return dim, nil
+}
+
+func quickhulld_serial[T Coord](ctx context.Context, points [][]T, dim int) (*HullD, error) {
+	q := &qhd[T]{ctx: ctx, points: points, dim: dim}
+	return q.build()
+}
+
+func quickhulld_parallel[T Coord](ctx context.Context, points [][]T, dim int) (*HullD, error) {
+	q := &qhd[T]{ctx: ctx, points: points, dim: dim, parallel: true}
+	return q.build()
+}
+
+// QuickhullDSerial computes the convex hull of d-dimensional points (every point has d coordinates,
+// 2 <= d <= MaxDimension) with sequential Quickhull. Returns ErrDegenerate if the points do not
+// span a volume.
+func QuickhullDSerial[T Coord](ctx context.Context, points [][]T) (*HullD, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dim, err := check_dimension(points)
+	if err != nil {
+		return nil, err
+	}
+	return quickhulld_serial(ctx, points, dim)
+}
+
+// QuickhullDParallel computes the convex hull of d-dimensional points like QuickhullDSerial,
+// distributing large conflict lists to the new faces in parallel
+func QuickhullDParallel[T Coord](ctx context.Context, points [][]T) (*HullD, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	dim, err := check_dimension(points)
+	if err != nil {
+		return nil, err
+	}
+	return quickhulld_parallel(ctx, points, dim)
+}
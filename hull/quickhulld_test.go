@@ -0,0 +1,280 @@
+package hull
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Both d-dimensional algorithms on points, which they must agree on
+func hulld_both[T Coord](t *testing.T, points [][]T) (*HullD, error) {
+	t.Helper()
+	serial, err := QuickhullDSerial(context.Background(), points)
+	parallel, perr := QuickhullDParallel(context.Background(), points)
+	if !errors.Is(perr, err) {
+		t.Fatalf("serial returned %v, parallel %v", err, perr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(serial.Vertices, parallel.Vertices) || !slices.EqualFunc(serial.Facets, parallel.Facets, func(a, b Facet) bool {
+		return slices.Equal(a.Vertices, b.Vertices)
+	}) {
+		t.Fatalf("serial and parallel hulls differ:\n%v\n%v", serial, parallel)
+	}
+	return serial, nil
+}
+
+// Check that every facet's normal is a unit vector pointing out of the hull: every point is on or
+// behind the facet, and the facet's corners are on it
+func check_hulld[T Coord](t *testing.T, points [][]T, h *HullD) {
+	t.Helper()
+	scale := 1.0
+	for _, p := range points {
+		for _, x := range p {
+			scale = math.Max(scale, math.Abs(float64(x)))
+		}
+	}
+	slack := 1e-9 * scale
+	dot := func(n []float64, p []T) float64 {
+		s := 0.0
+		for i := range n {
+			s += n[i] * float64(p[i])
+		}
+		return s
+	}
+	for _, f := range h.Facets {
+		if len(f.Normal) != h.Dim || len(f.Vertices) < h.Dim || !slices.IsSorted(f.Vertices) {
+			t.Fatalf("facet %v has %d normal coordinates and %d corners in %d dimensions", f.Vertices, len(f.Normal), len(f.Vertices), h.Dim)
+		}
+		norm := 0.0
+		for _, x := range f.Normal {
+			norm += x * x
+		}
+		if math.Abs(math.Sqrt(norm)-1) > 1e-9 {
+			t.Fatalf("facet %v normal %v is not a unit vector", f.Vertices, f.Normal)
+		}
+		for i, p := range points {
+			if dot(f.Normal, p) > f.Offset+slack {
+				t.Fatalf("point %d %v is in front of facet %v (normal %v, offset %g)", i, p, f.Vertices, f.Normal, f.Offset)
+			}
+		}
+		for _, v := range f.Vertices {
+			if math.Abs(dot(f.Normal, points[v])-f.Offset) > slack {
+				t.Fatalf("corner %d %v is off facet %v", v, points[v], f.Vertices)
+			}
+			if !slices.Contains(h.Vertices, v) {
+				t.Fatalf("corner %d of facet %v is not a hull vertex", v, f.Vertices)
+			}
+		}
+	}
+}
+
+// Hull vertices of h as points, in lexicographic order
+func vertex_points[T Coord](points [][]T, h *HullD) [][]T {
+	var res [][]T
+	for _, v := range h.Vertices {
+		res = append(res, points[v])
+	}
+	slices.SortFunc(res, slices.Compare[[]T])
+	return res
+}
+
+func TestQuickhullDMatches2D(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 30; trial++ {
+		points := random_points[int32](r, 3+r.Intn(100))
+		if trial%2 == 0 {
+			// Small grid, so plenty of collinear points and duplicates
+			for i := range points {
+				points[i][0] %= 5
+				points[i][1] %= 5
+			}
+		}
+		want, err := SeqMonotoneChain(context.Background(), slices.Clone(points), Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pointsd := make([][]int32, len(points))
+		for i, p := range points {
+			pointsd[i] = []int32{p[0], p[1]}
+		}
+		h, err := hulld_both(t, pointsd)
+		if len(want) < 3 {
+			if !errors.Is(err, ErrDegenerate) {
+				t.Fatalf("collinear %v: got %v, want %v", points, err, ErrDegenerate)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_hulld(t, pointsd, h)
+
+		var wantd [][]int32
+		for _, p := range want {
+			wantd = append(wantd, []int32{p[0], p[1]})
+		}
+		slices.SortFunc(wantd, slices.Compare[[]int32])
+		if got := vertex_points(pointsd, h); !slices.EqualFunc(got, wantd, slices.Equal[[]int32]) || len(h.Facets) != len(want) {
+			t.Fatalf("hull of %v: got vertices %v and %d facets, want %v", points, got, len(h.Facets), want)
+		}
+	}
+}
+
+func TestQuickhullDMatches3D(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 30; trial++ {
+		points := make([][3]float64, 4+r.Intn(60))
+		pointsd := make([][]float64, len(points))
+		for i := range points {
+			points[i] = [3]float64{r.NormFloat64(), r.NormFloat64(), r.NormFloat64()}
+			pointsd[i] = points[i][:]
+		}
+		want, err := Quickhull3DSerial(context.Background(), slices.Clone(points))
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := hulld_both(t, pointsd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_hulld(t, pointsd, h)
+
+		got := vertex_points(pointsd, h)
+		if !slices.EqualFunc(got, want.Vertices, func(a []float64, b [3]float64) bool { return slices.Equal(a, b[:]) }) {
+			t.Fatalf("got vertices %v, want %v", got, want.Vertices)
+		}
+		// In general position every facet is a triangle, so the facets are the 3D faces
+		var got_faces, want_faces [][3][3]float64
+		for _, f := range h.Facets {
+			face := [3][3]float64{points[f.Vertices[0]], points[f.Vertices[1]], points[f.Vertices[2]]}
+			slices.SortFunc(face[:], compare3d[float64])
+			got_faces = append(got_faces, face)
+		}
+		for _, f := range want.Faces {
+			face := [3][3]float64{want.Vertices[f[0]], want.Vertices[f[1]], want.Vertices[f[2]]}
+			slices.SortFunc(face[:], compare3d[float64])
+			want_faces = append(want_faces, face)
+		}
+		compare_faces := func(a, b [3][3]float64) int {
+			return slices.CompareFunc(a[:], b[:], compare3d[float64])
+		}
+		slices.SortFunc(got_faces, compare_faces)
+		slices.SortFunc(want_faces, compare_faces)
+		if !slices.Equal(got_faces, want_faces) {
+			t.Fatalf("got facets %v, want faces %v", got_faces, want_faces)
+		}
+	}
+}
+
+// Points of the grid {0, 1, 2}^dim: the corners of a cube, its center and points in the middle of
+// each of its faces of every dimension
+func cube_grid(dim int) [][]int64 {
+	points := [][]int64{{}}
+	for d := 0; d < dim; d++ {
+		var next [][]int64
+		for _, p := range points {
+			for x := int64(0); x <= 2; x++ {
+				next = append(next, append(slices.Clone(p), x))
+			}
+		}
+		points = next
+	}
+	return points
+}
+
+func TestQuickhullDCube(t *testing.T) {
+	for dim := 2; dim <= 5; dim++ {
+		points := cube_grid(dim)
+		h, err := hulld_both(t, points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_hulld(t, points, h)
+		if len(h.Vertices) != 1<<dim || len(h.Facets) != 2*dim {
+			t.Fatalf("dim %d: got %d vertices and %d facets, want %d and %d", dim, len(h.Vertices), len(h.Facets), 1<<dim, 2*dim)
+		}
+		for _, f := range h.Facets {
+			if len(f.Vertices) != 1<<(dim-1) {
+				t.Fatalf("dim %d: facet %v does not have %d corners", dim, f.Vertices, 1<<(dim-1))
+			}
+			for _, v := range f.Vertices {
+				if slices.Contains(points[v], 1) {
+					t.Fatalf("dim %d: %v is not a corner", dim, points[v])
+				}
+			}
+		}
+	}
+}
+
+func TestQuickhullDDegenerate(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	// On the hyperplane x3 = x0 + x1 - x2
+	var hyperplane [][]int64
+	for i := 0; i < 100; i++ {
+		x := []int64{int64(r.Intn(50)), int64(r.Intn(50)), int64(r.Intn(50))}
+		hyperplane = append(hyperplane, append(x, x[0]+x[1]-x[2]))
+	}
+	// d+1 points, but repeats of only d of them
+	var repeated [][]int64
+	for i := 0; i < 5; i++ {
+		repeated = append(repeated, []int64{0, 0, 0, 0}, []int64{1, 0, 0, 0}, []int64{0, 1, 0, 0}, []int64{0, 0, 1, 0})
+	}
+	tests := []struct {
+		name   string
+		points [][]int64
+	}{
+		{"empty", nil},
+		{"one point", [][]int64{{1, 2, 3, 4}}},
+		{"collinear", [][]int64{{0, 0, 0}, {1, 2, 3}, {2, 4, 6}, {-1, -2, -3}}},
+		{"coplanar", [][]int64{{0, 0, 5}, {1, 0, 5}, {0, 1, 5}, {3, 3, 5}, {1, 1, 5}}},
+		{"hyperplane", hyperplane},
+		{"repeated", repeated},
+	}
+	for _, test := range tests {
+		if _, err := hulld_both(t, test.points); !errors.Is(err, ErrDegenerate) {
+			t.Fatalf("%s: got %v, want %v", test.name, err, ErrDegenerate)
+		}
+	}
+
+	// One point off the hyperplane gives it volume
+	h, err := hulld_both(t, append(slices.Clone(hyperplane), []int64{0, 0, 0, 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check_hulld(t, append(hyperplane, []int64{0, 0, 0, 1}), h)
+}
+
+func TestQuickhullDRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for _, dim := range []int{4, 5, 6} {
+		points := make([][]float64, 300)
+		for i := range points {
+			points[i] = make([]float64, dim)
+			for j := range points[i] {
+				points[i][j] = r.NormFloat64()
+			}
+		}
+		h, err := hulld_both(t, points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check_hulld(t, points, h)
+	}
+
+	// Enough points for the parallel variant to hand out conflict lists in parallel
+	points := make([][]float64, 4*PAR_QH3_LIMIT)
+	for i := range points {
+		points[i] = []float64{r.NormFloat64(), r.NormFloat64(), r.NormFloat64(), r.NormFloat64()}
+	}
+	h, err := hulld_both(t, points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check_hulld(t, points, h)
+}
@@ -0,0 +1,202 @@
+package hull
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Capability flags describe the shape of the hull an algorithm returns
+type Capability uint
+
+const (
+	// Hull is returned as a canonical polygon (see Options)
+	OrderedHull Capability = 1 << iota
+	// Collinear points on the hull boundary can be kept (Options.Collinear)
+	KeepsCollinear
+	// Running time depends on the number of hull points h, not just n
+	OutputSensitive
+	// Input slice is reordered or overwritten
+	ModifiesInput
+)
+
+// Has reports whether all flags in c are set
+func (caps Capability) Has(c Capability) bool {
+	return caps&c == c
+}
+
+// HullAlgorithm is a convex hull implementation for T coordinates that can be registered and run by name
+type HullAlgorithm[T Coord] interface {
+	// Unique name, e.g. "serial_graham"
+	Name() string
+	// Short name shared by variants of the same algorithm, e.g. "grah"
+	Family() string
+	Parallel() bool
+	// Asymptotic running time, e.g. "O(n log h)"
+	Complexity() string
+	Capabilities() Capability
+	// Compute the hull of points, returns ctx.Err() if ctx is cancelled first
+	Hull(ctx context.Context, points [][2]T, opts Options) ([][2]T, error)
+}
+
+// Function backed HullAlgorithm
+type algorithm[T Coord] struct {
+	name       string
+	family     string
+	parallel   bool
+	complexity string
+	caps       Capability
+	run        func(context.Context, [][2]T, Options) ([][2]T, error)
+}
+
+func (a *algorithm[T]) Name() string             { return a.name }
+func (a *algorithm[T]) Family() string           { return a.family }
+func (a *algorithm[T]) Parallel() bool           { return a.parallel }
+func (a *algorithm[T]) Complexity() string       { return a.complexity }
+func (a *algorithm[T]) Capabilities() Capability { return a.caps }
+func (a *algorithm[T]) Hull(ctx context.Context, points [][2]T, opts Options) ([][2]T, error) {
+	return a.run(ctx, points, opts)
+}
+
+// NewAlgorithm wraps a hull function so it can be registered
+func NewAlgorithm[T Coord](name, family string, parallel bool, complexity string, caps Capability, run func(context.Context, [][2]T, Options) ([][2]T, error)) HullAlgorithm[T] {
+	return &algorithm[T]{name, family, parallel, complexity, caps, run}
+}
+
+// Algorithms registered for one coordinate type
+type registry[T Coord] struct {
+	sync.RWMutex
+	byName map[string]HullAlgorithm[T]
+	// Registration order, so runs are reported in a stable order
+	order []HullAlgorithm[T]
+}
+
+// Each coordinate type has its own registry, names only need to be unique within one
+var registries struct {
+	f32 registry[float32]
+	f64 registry[float64]
+	i32 registry[int32]
+	i64 registry[int64]
+}
+
+// Registry of algorithms for T coordinates
+func registry_for[T Coord]() *registry[T] {
+	var reg any
+	switch any(*new(T)).(type) {
+	case float32:
+		reg = &registries.f32
+	case float64:
+		reg = &registries.f64
+	case int32:
+		reg = &registries.i32
+	case int64:
+		reg = &registries.i64
+	}
+	return reg.(*registry[T])
+}
+
+// Register makes an algorithm available by name for its coordinate type.
+// Panics if the name is empty or already registered.
+func Register[T Coord](alg HullAlgorithm[T]) {
+	registry := registry_for[T]()
+	registry.Lock()
+	defer registry.Unlock()
+	name := alg.Name()
+	if name == "" {
+		panic("hull: Register algorithm with empty name")
+	}
+	if _, dup := registry.byName[name]; dup {
+		panic("hull: Register called twice for algorithm " + name)
+	}
+	if registry.byName == nil {
+		registry.byName = make(map[string]HullAlgorithm[T])
+	}
+	registry.byName[name] = alg
+	registry.order = append(registry.order, alg)
+}
+
+// Lookup finds a registered algorithm for T coordinates by name
+func Lookup[T Coord](name string) (HullAlgorithm[T], bool) {
+	registry := registry_for[T]()
+	registry.RLock()
+	defer registry.RUnlock()
+	alg, ok := registry.byName[name]
+	return alg, ok
+}
+
+// Algorithms returns every algorithm registered for T coordinates in registration order
+func Algorithms[T Coord]() []HullAlgorithm[T] {
+	registry := registry_for[T]()
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]HullAlgorithm[T](nil), registry.order...)
+}
+
+// Resolve turns a comma separated list of algorithm names and/or families into algorithms.
+// An empty spec selects every registered algorithm.
+func Resolve[T Coord](spec string) ([]HullAlgorithm[T], error) {
+	all := Algorithms[T]()
+	if strings.TrimSpace(spec) == "" {
+		return all, nil
+	}
+
+	var algs []HullAlgorithm[T]
+	seen := make(map[string]bool)
+	for _, token := range strings.Split(spec, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		found := false
+		for _, alg := range all {
+			if alg.Name() == token || alg.Family() == token {
+				found = true
+				if !seen[alg.Name()] {
+					seen[alg.Name()] = true
+					algs = append(algs, alg)
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("hull: unknown algorithm %q (registered: %s)", token, strings.Join(names(all), ", "))
+		}
+	}
+	return algs, nil
+}
+
+// Sorted names of algs
+func names[T Coord](algs []HullAlgorithm[T]) []string {
+	res := make([]string, len(algs))
+	for i, alg := range algs {
+		res[i] = alg.Name()
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Register the algorithms implemented in this package for every coordinate type
+func init() {
+	register_builtins[float32]()
+	register_builtins[float64]()
+	register_builtins[int32]()
+	register_builtins[int64]()
+}
+
+func register_builtins[T Coord]() {
+	Register(NewAlgorithm("serial_jarvis", "jarv", false, "O(nh)", OrderedHull|KeepsCollinear|OutputSensitive, SeqJarvis[T]))
+	Register(NewAlgorithm("parallel_jarvis", "jarv", true, "O(nh)", OrderedHull|KeepsCollinear|OutputSensitive, ParallelJarvis[T]))
+	Register(NewAlgorithm("serial_graham", "grah", false, "O(n log n)", OrderedHull|KeepsCollinear|ModifiesInput, SeqGrahamScan[T]))
+	Register(NewAlgorithm("parallel_graham", "grah", true, "O(n log n)", OrderedHull|KeepsCollinear|ModifiesInput, ParallelGrahamScan[T]))
+	Register(NewAlgorithm("serial_monotone", "mono", false, "O(n log n)", OrderedHull|KeepsCollinear|ModifiesInput, SeqMonotoneChain[T]))
+	Register(NewAlgorithm("parallel_monotone", "mono", true, "O(n log n)", OrderedHull|KeepsCollinear|ModifiesInput, ParallelMonotoneChain[T]))
+	Register(NewAlgorithm("serial_chans", "chan", false, "O(n log h)", OrderedHull|KeepsCollinear|OutputSensitive|ModifiesInput, SeqChans[T]))
+	Register(NewAlgorithm("parallel_chans", "chan", true, "O(n log h)", OrderedHull|KeepsCollinear|OutputSensitive|ModifiesInput, ParallelChans[T]))
+	Register(NewAlgorithm("serial_ks", "kirk", false, "O(n log h)", OrderedHull|KeepsCollinear|OutputSensitive, SeqKirkpatrickSeidel[T]))
+	Register(NewAlgorithm("parallel_ks", "kirk", true, "O(n log h)", OrderedHull|KeepsCollinear|OutputSensitive, ParallelKirkpatrickSeidel[T]))
+	Register(NewAlgorithm("serial_dc", "divi", false, "O(n log n)", OrderedHull|KeepsCollinear|ModifiesInput, SeqDivideConquer[T]))
+	Register(NewAlgorithm("parallel_dc", "divi", true, "O(n log n)", OrderedHull|KeepsCollinear|ModifiesInput, ParallelDivideConquer[T]))
+	Register(NewAlgorithm("serial_qh", "quic", false, "O(n log n)", OrderedHull|KeepsCollinear, QuickhullSerial[T]))
+	Register(NewAlgorithm("parallel_qh", "quic", true, "O(n log n)", OrderedHull|KeepsCollinear, QuickhullParallel[T]))
+}
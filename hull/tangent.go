@@ -0,0 +1,132 @@
+package hull
+
+import (
+	"errors"
+	"sort"
+)
+
+/**********************
+ * Tangents           *
+ **********************
+Tangents and the visible chain from a point outside a stored hull, in O(log h) exact tests.
+
+An edge is visible from p when p is strictly to its right, and the visible edges form one chain
+whose ends are the tangent vertices. The fan around hull[0] (as in locate) gives one visible edge,
+whose first vertex r is then visible too. The line from p through r enters the hull at r and leaves
+it once, splitting the boundary into the arc counterclockwise from r, where vertices are right of
+the line, and the arc back to r. Each arc holds one end of the visible chain and is visible then
+invisible (or the reverse) along it, so three binary searches find the arcs and the two ends. This
+replaces the casework of find_tangent_bsearch with monotone predicates only.
+*/
+
+var (
+	ErrPointInside     = errors.New("hull: point is inside the polygon")
+	ErrPointOnBoundary = errors.New("hull: point is on the boundary of the polygon")
+)
+
+// Visibility is what a point outside a convex polygon sees of it. Indices refer to the polygon.
+type Visibility struct {
+	// Tangent vertices, on the left and on the right seen from the point. The polygon lies
+	// between the rays from the point through them.
+	Left, Right int
+	// Vertices seen from the point, from Left to Right, joined by the edges facing it. Edges seen
+	// edge-on (the point is on their line) are not visible, so Left and Right are the nearer
+	// ends of such edges.
+	Chain []int
+}
+
+// Tangents returns the tangents and visible chain of the polygon from p, or ErrPointInside or
+// ErrPointOnBoundary if p is not outside. The chain between Left and Right is what replaces p's
+// hull when p is added to it. O(log h) plus the length of the chain
+func (l *Locator[T]) Tangents(p [2]T) (*Visibility, error) {
+	switch l.Locate(p) {
+	case Inside:
+		return nil, ErrPointInside
+	case Boundary:
+		return nil, ErrPointOnBoundary
+	}
+
+	h := l.hull
+	n := len(h)
+	switch n {
+	case 1:
+		return &Visibility{Chain: []int{l.index[0]}, Left: l.index[0], Right: l.index[0]}, nil
+	case 2:
+		a, b := 0, 1
+		switch orient(h[0], h[1], p) {
+		case 0:
+			// On the segment's line, only the nearer end is seen
+			if along(h[0], h[1], h[1], p) > 0 {
+				a = 1
+			}
+			b = a
+		case 1:
+			a, b = 1, 0
+		}
+		chain := []int{l.index[a]}
+		if b != a {
+			chain = append(chain, l.index[b])
+		}
+		return &Visibility{Chain: chain, Left: l.index[a], Right: l.index[b]}, nil
+	}
+
+	// Relative to the visible vertex r
+	r := visible_edge(h, p)
+	w := func(j int) [2]T { return h[mod(r+j, n)] }
+	visible := func(j int) bool { return orient(w(j), w(j+1), p) < 0 }
+
+	// Arc split: the first vertex not right of the line from p through r
+	split := sort.Search(n-1, func(i int) bool { return orient(p, w(0), w(i+1)) >= 0 }) + 1
+	// Right end: the first invisible edge after r
+	right := sort.Search(split, func(j int) bool { return !visible(j) })
+	// Left end: the first visible edge after the split, or r itself
+	left := split + sort.Search(n-split, func(i int) bool { return visible(split + i) })
+
+	chain := make([]int, 0, n-left+right+1)
+	for j := left; j <= n+right; j++ {
+		chain = append(chain, l.index[mod(r+j, n)])
+	}
+	return &Visibility{Chain: chain, Left: chain[0], Right: chain[len(chain)-1]}, nil
+}
+
+// Tangents returns the tangents and visible chain of a convex polygon (see NewLocator) from p. Use
+// a Locator to query the same polygon repeatedly.
+func Tangents[T Coord](polygon [][2]T, p [2]T) (*Visibility, error) {
+	l, err := NewLocator(polygon)
+	if err != nil {
+		return nil, err
+	}
+	return l.Tangents(p)
+}
+
+// First vertex of an edge of hull (counterclockwise, strictly convex, at least 3 vertices) that is
+// visible from p, which is outside it. O(log h)
+func visible_edge[T Coord](hull [][2]T, p [2]T) int {
+	h := len(hull)
+	v0 := hull[0]
+	first := orient(v0, hull[1], p)
+	last := orient(v0, hull[h-1], p)
+	switch {
+	case first < 0:
+		return 0
+	case last > 0:
+		return h - 1
+	case first == 0:
+		// On the line through the first edge past hull[1] (behind hull[0] it would see the last edge)
+		return 1
+	case last == 0:
+		return h - 2
+	}
+
+	// Inside the angle at v0, the edge across the wedge holding p faces it
+	lo, hi := 1, h-1
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if orient(v0, hull[mid], p) >= 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
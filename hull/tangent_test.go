@@ -0,0 +1,139 @@
+package hull
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Visible chain of a strictly convex counterclockwise hull from p outside it, by testing every
+// edge: from the first vertex of the first visible edge to the last vertex of the last one. On the
+// line of a segment (or a single point) only the nearer end is seen.
+func brute_force_chain[T Coord](hull [][2]T, p [2]T) [][2]T {
+	n := len(hull)
+	visible := func(i int) bool { return orient(hull[mod(i+n, n)], hull[mod(i+n+1, n)], p) < 0 }
+	left := -1
+	for i := 0; i < n; i++ {
+		if visible(i) && !visible(i-1) {
+			left = i
+		}
+	}
+	if left < 0 {
+		nearest := hull[0]
+		if n == 2 && along(hull[0], hull[1], hull[1], p) > 0 {
+			nearest = hull[1]
+		}
+		return [][2]T{nearest}
+	}
+	chain := [][2]T{hull[left]}
+	for i := left; visible(i); i++ {
+		chain = append(chain, hull[mod(i+1, n)])
+	}
+	return chain
+}
+
+// Check v against brute force: the chain, and that the polygon is right of the ray from p through
+// Left and left of the one through Right
+func check_visibility[T Coord](t *testing.T, strict, polygon [][2]T, p [2]T, v *Visibility) {
+	t.Helper()
+	var chain [][2]T
+	for _, i := range v.Chain {
+		chain = append(chain, polygon[i])
+	}
+	if want := brute_force_chain(strict, p); !slices.Equal(chain, want) {
+		t.Fatalf("chain of %v from %v: got %v, want %v", polygon, p, chain, want)
+	}
+	if v.Left != v.Chain[0] || v.Right != v.Chain[len(v.Chain)-1] {
+		t.Fatalf("chain of %v from %v: %v does not run from %d to %d", polygon, p, v.Chain, v.Left, v.Right)
+	}
+	for _, q := range strict {
+		if orient(p, polygon[v.Left], q) > 0 || orient(p, polygon[v.Right], q) < 0 {
+			t.Fatalf("%v from %v: %v is outside the tangents through %v and %v", polygon, p, q, polygon[v.Left], polygon[v.Right])
+		}
+	}
+}
+
+func TestTangentsBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	on_extension := 0
+	for trial := 0; trial < 300; trial++ {
+		size := 2 + r.Intn(10)
+		strict, variants := hull_variants(t, grid_points(r, 1+r.Intn(20), size))
+		queries := grid_queries(strict, size)
+		for _, polygon := range variants {
+			l, err := NewLocator(polygon)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, q := range queries {
+				v, err := l.Tangents(q)
+				switch brute_force_locate(strict, q) {
+				case Inside:
+					if !errors.Is(err, ErrPointInside) {
+						t.Fatalf("%v in %v: got %v, want %v", q, polygon, err, ErrPointInside)
+					}
+					continue
+				case Boundary:
+					if !errors.Is(err, ErrPointOnBoundary) {
+						t.Fatalf("%v on %v: got %v, want %v", q, polygon, err, ErrPointOnBoundary)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%v outside %v: %v", q, polygon, err)
+				}
+				check_visibility(t, strict, polygon, q, v)
+				for i, a := range strict {
+					if len(strict) > 2 && orient(a, strict[(i+1)%len(strict)], q) == 0 {
+						on_extension++
+					}
+				}
+			}
+		}
+	}
+	if on_extension == 0 {
+		t.Fatalf("no query was on the extension of an edge")
+	}
+}
+
+func TestTangentsCases(t *testing.T) {
+	square := [][2]int64{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	tests := []struct {
+		name    string
+		polygon [][2]int64
+		p       [2]int64
+		chain   []int
+	}{
+		{"facing an edge", square, [2]int64{1, -5}, []int{0, 1}},
+		{"facing a corner", square, [2]int64{3, -1}, []int{0, 1, 2}},
+		{"extension of an edge, ahead", square, [2]int64{3, 0}, []int{1, 2}},
+		{"extension of an edge, behind", square, [2]int64{-1, 0}, []int{3, 0}},
+		{"extension of two edges", square, [2]int64{4, 2}, []int{1, 2}},
+		{"far corner", square, [2]int64{-3, 5}, []int{2, 3, 0}},
+		{"clockwise", [][2]int64{{0, 0}, {0, 2}, {2, 2}, {2, 0}}, [2]int64{1, -5}, []int{0, 3}},
+		{"collinear boundary points", [][2]int64{{0, 0}, {1, 0}, {2, 0}, {2, 2}, {0, 2}}, [2]int64{1, -1}, []int{0, 2}},
+		{"point", [][2]int64{{1, 1}}, [2]int64{0, 0}, []int{0}},
+		{"segment, beside", [][2]int64{{0, 0}, {2, 0}}, [2]int64{1, -5}, []int{0, 1}},
+		{"segment, other side", [][2]int64{{0, 0}, {2, 0}}, [2]int64{1, 5}, []int{1, 0}},
+		{"segment, on its line", [][2]int64{{0, 0}, {2, 0}}, [2]int64{5, 0}, []int{1}},
+	}
+	for _, test := range tests {
+		v, err := Tangents(test.polygon, test.p)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !slices.Equal(v.Chain, test.chain) {
+			t.Fatalf("%s: got chain %v, want %v", test.name, v.Chain, test.chain)
+		}
+	}
+
+	if _, err := Tangents(square, [2]int64{1, 1}); !errors.Is(err, ErrPointInside) {
+		t.Fatalf("inside: got %v, want %v", err, ErrPointInside)
+	}
+	for _, p := range [][2]int64{{1, 0}, {2, 2}} {
+		if _, err := Tangents(square, p); !errors.Is(err, ErrPointOnBoundary) {
+			t.Fatalf("%v: got %v, want %v", p, err, ErrPointOnBoundary)
+		}
+	}
+}
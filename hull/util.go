@@ -0,0 +1,104 @@
+package hull
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// Get index of leftmost point (lowest of ties, so it is a hull vertex)
+func leftmost[T Coord](points [][2]T) int {
+	var min T
+	index := -1
+	for i, point := range points {
+		if index == -1 || point[0] < min || (point[0] == min && point[1] < points[index][1]) {
+			index = i
+			min = point[0]
+		}
+	}
+	return index
+}
+
+// Get index of rightmost point (highest of ties)
+func rightmost[T Coord](points [][2]T) int {
+	var max T
+	index := -1
+	for i, point := range points {
+		if index == -1 || point[0] > max || (point[0] == max && point[1] > points[index][1]) {
+			index = i
+			max = point[0]
+		}
+	}
+	return index
+}
+
+// Get index of lowest point (rightmost of ties)
+func lowest[T Coord](points [][2]T) int {
+	var min T
+	index := -1
+	for i, point := range points {
+		if index == -1 || point[1] < min || (point[1] == min && point[0] > points[index][0]) {
+			index = i
+			min = point[1]
+		}
+	}
+	return index
+}
+
+// Get index of highest point (leftmost of ties)
+func highest[T Coord](points [][2]T) int {
+	var max T
+	index := -1
+	for i, point := range points {
+		if index == -1 || point[1] > max || (point[1] == max && point[0] < points[index][0]) {
+			index = i
+			max = point[1]
+		}
+	}
+	return index
+}
+
+// Workaround in case of negative issues
+func mod(a, b int) int {
+	// return (a%b + b) % b
+	return a % b
+}
+
+// Distance between two points
+func dist[T Coord](a, b [2]T) float64 {
+	return math.Hypot(float64(a[0])-float64(b[0]), float64(a[1])-float64(b[1]))
+}
+
+// Whether ctx has been cancelled, cheap enough to poll in loops (ctx.Err takes a lock)
+func done(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// How many iterations of a loop over the points run between checks of ctx
+const poll_interval = 1 << 12
+
+// Whether ctx has been cancelled, checked every poll_interval iterations of loop index i
+func poll(ctx context.Context, i int) bool {
+	return i&(poll_interval-1) == 0 && done(ctx)
+}
+
+// Add in/remove println debugging
+func debug(a ...interface{}) {
+	// change this to switch on/off (intentionally manual to allow for easy compiler opt out)
+	if false {
+		fmt.Println(a...)
+	}
+}
+
+// Timing metrics reporting, can defer
+func timeTrack(start time.Time, name string) {
+	elapsed := time.Since(start)
+	log.Printf("%s took %s", name, elapsed)
+}
@@ -0,0 +1,168 @@
+package hull
+
+import (
+	"context"
+	"time"
+)
+
+/**********************
+ * Sliding window     *
+ **********************
+Hull of the last points of a stream, kept as a two-stack queue of partial hulls. New points go on
+the back stack, whose hull is an incremental Hull. Old points leave from the front stack. When the
+front runs out, the back is flipped into a new front by adding its points newest first to another
+incremental Hull, logging what each add changed: whether the point went into each chain and the
+vertices it removed. The oldest point was added last, so dropping it undoes the latest logged add and
+leaves the hull of the remaining suffix. Every vertex is removed at most once per flip, so the log
+is O(W) and each point costs O(log h) amortized to flip and to drop.
+
+The hull of the window is the union hull of the front and back hulls, merged in O(h).
+*/
+
+// TimedPoint is a point of a stream with its arrival time
+type TimedPoint[T Coord] struct {
+	Point [2]T
+	At    time.Time
+}
+
+// WindowHull is the convex hull of the most recent points of a stream: the last size points, those
+// that arrived within duration of the newest one, or both (0 for no limit). A WindowHull is not
+// safe for concurrent use.
+type WindowHull[T Coord] struct {
+	size     int
+	duration time.Duration
+
+	// Oldest points and their hull. front_undo[i] undoes the add of front[i] to front_hull.
+	front      []TimedPoint[T]
+	front_hull *Hull[T]
+	front_undo []hull_undo
+	// Vertices removed by the logged adds, those of front[0] last
+	front_removed [][2]T
+	// Newest points and their hull
+	back      []TimedPoint[T]
+	back_hull *Hull[T]
+}
+
+// NewWindowHull returns an empty WindowHull over the last size points and the last duration of
+// arrivals (0 for no limit)
+func NewWindowHull[T Coord](size int, duration time.Duration) *WindowHull[T] {
+	return &WindowHull[T]{size: size, duration: duration, front_hull: NewHull[T](), back_hull: NewHull[T]()}
+}
+
+// Change made by adding a point to a Hull: whether it went into the lower and upper chains, and
+// how many vertices it removed from each
+type hull_undo struct {
+	lower, upper                 bool
+	lower_removed, upper_removed int
+}
+
+// Len returns the number of points in the window
+func (w *WindowHull[T]) Len() int {
+	return len(w.front) + len(w.back)
+}
+
+// Push adds p, which arrived at at, and drops the points that are no longer in the window.
+// Arrival times must not decrease.
+func (w *WindowHull[T]) Push(p [2]T, at time.Time) {
+	w.back = append(w.back, TimedPoint[T]{p, at})
+	w.back_hull.Add(p)
+	if w.size > 0 {
+		for w.Len() > w.size {
+			w.pop()
+		}
+	}
+	w.Expire(at)
+}
+
+// Expire drops the points that arrived more than the window duration before now
+func (w *WindowHull[T]) Expire(now time.Time) {
+	if w.duration <= 0 {
+		return
+	}
+	for w.Len() > 0 && now.Sub(w.oldest().At) > w.duration {
+		w.pop()
+	}
+}
+
+// Oldest point in the window, which must not be empty
+func (w *WindowHull[T]) oldest() TimedPoint[T] {
+	if len(w.front) == 0 {
+		return w.back[0]
+	}
+	return w.front[0]
+}
+
+// Drop the oldest point
+func (w *WindowHull[T]) pop() {
+	if len(w.front) == 0 {
+		w.flip()
+	}
+	// Undo the chains in the reverse of the order add changed them
+	p, u := w.front[0].Point, w.front_undo[0]
+	upper := len(w.front_removed) - u.upper_removed
+	w.front_hull.upper.undo_add(p, u.upper, w.front_removed[upper:])
+	lower := upper - u.lower_removed
+	w.front_hull.lower.undo_add(p, u.lower, w.front_removed[lower:upper])
+	w.front_removed = w.front_removed[:lower]
+
+	w.front = w.front[1:]
+	w.front_undo = w.front_undo[1:]
+}
+
+// Move the back stack to the (empty) front, logging how to undo the add of each point
+func (w *WindowHull[T]) flip() {
+	w.front = w.back
+	w.front_hull = NewHull[T]()
+	w.front_undo = make([]hull_undo, len(w.front))
+	w.front_removed = w.front_removed[:0]
+	for i := len(w.front) - 1; i >= 0; i-- {
+		p, u := w.front[i].Point, &w.front_undo[i]
+		n := len(w.front_removed)
+		u.lower = w.front_hull.lower.add(p, &w.front_removed)
+		u.lower_removed = len(w.front_removed) - n
+		n = len(w.front_removed)
+		u.upper = w.front_hull.upper.add(p, &w.front_removed)
+		u.upper_removed = len(w.front_removed) - n
+	}
+	w.back = nil
+	w.back_hull = NewHull[T]()
+}
+
+// Vertices returns the hull of the window in canonical form (counterclockwise from the lowest,
+// then leftmost vertex, without collinear boundary points)
+func (w *WindowHull[T]) Vertices() [][2]T {
+	front, back := w.front_hull.Vertices(), w.back_hull.Vertices()
+	if len(front) == 0 && len(back) == 0 {
+		return front
+	}
+	// Both are hulls, so the only error is for two empty ones
+	hull, _ := UnionHull(front, back)
+	return hull
+}
+
+// WindowStream reads points from in and sends the hull of the window (see WindowHull) after each
+// arrival. The output channel is closed once in is closed or ctx is cancelled.
+func WindowStream[T Coord](ctx context.Context, in <-chan TimedPoint[T], size int, duration time.Duration) <-chan [][2]T {
+	out := make(chan [][2]T)
+	go func() {
+		defer close(out)
+		w := NewWindowHull[T](size, duration)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case p, ok := <-in:
+				if !ok {
+					return
+				}
+				w.Push(p.Point, p.At)
+				select {
+				case out <- w.Vertices():
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,81 @@
+package hull
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// Push n points from gen into a WindowHull and check its hull against a from-scratch hull of the
+// points still in the window after every push. Points arrive a second apart.
+func check_window[T Coord](t *testing.T, r *rand.Rand, n, size int, duration time.Duration, gen func() [2]T) {
+	t.Helper()
+	w := NewWindowHull[T](size, duration)
+	var stream [][2]T
+	start := time.Unix(0, 0)
+	for i := 0; i < n; i++ {
+		stream = append(stream, gen())
+		now := start.Add(time.Duration(i) * time.Second)
+		w.Push(stream[i], now)
+
+		// The window holds the last size points that arrived within duration of now
+		first := 0
+		if size > 0 {
+			first = max(first, len(stream)-size)
+		}
+		if duration > 0 {
+			first = max(first, i-int(duration/time.Second))
+		}
+		points := stream[first:]
+		if w.Len() != len(points) {
+			t.Fatalf("push %d: %d points in the window, want %d", i, w.Len(), len(points))
+		}
+		got := w.Vertices()
+		want := graham_reference(t, points)
+		if !equal_hulls(got, want) {
+			t.Fatalf("push %d: hull of %v\ngot  %v\nwant %v", i, points, got, want)
+		}
+	}
+}
+
+func TestWindowHullUniform(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []int{1, 2, 3, 10, 100} {
+		check_window(t, r, 1000, size, 0, func() [2]float64 {
+			return [2]float64{r.Float64(), r.Float64()}
+		})
+	}
+}
+
+func TestWindowHullGrid(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, side := range []int{1, 2, 3, 8} {
+		for _, size := range []int{3, 17, 64} {
+			check_window(t, r, 500, size, 0, func() [2]int32 {
+				return [2]int32{int32(r.Intn(side)), int32(r.Intn(side))}
+			})
+		}
+	}
+}
+
+func TestWindowHullDuration(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	check_window(t, r, 500, 0, 20*time.Second, func() [2]int64 {
+		return [2]int64{int64(r.Intn(10)), int64(r.Intn(10))}
+	})
+	check_window(t, r, 500, 30, 20*time.Second, func() [2]int64 {
+		return [2]int64{int64(r.Intn(10)), int64(r.Intn(10))}
+	})
+}
+
+// Every point of a circle is on the hull, the worst case for the front stack
+func TestWindowHullConvexPosition(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	i := 0
+	check_window(t, r, 1500, 500, 0, func() [2]float64 {
+		angle := 2 * math.Pi * float64(i) / 997
+		i++
+		return [2]float64{math.Cos(angle), math.Sin(angle)}
+	})
+}
@@ -1,36 +1,82 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"runtime"
 	"runtime/pprof"
+	"strings"
 	"time"
+
+	"github.com/henryliu5/convex-hull/hull"
 )
 
-// Run convex hull using algorithm: method
-func run_hull(points [][2]float32, method func([][2]float32) [][2]float32, name string, trials int, save_time bool, result_file string, variable_of_interest string, do_output bool) {
+// Flags shared by every run
+type run_config struct {
+	algs         string
+	opts         hull.Options
+	timeout      time.Duration
+	trials       int
+	result_file  string
+	voi          string
+	do_output    bool
+	output       string
+	convert      string
+	skip_invalid bool
+	calipers     bool
+	enclosing    bool
+}
+
+// Run convex hull using algorithm: alg
+func run_hull[T hull.Coord](points [][2]T, alg hull.HullAlgorithm[T], opts hull.Options, timeout time.Duration, trials int, save_time bool, result_file string, variable_of_interest string, do_output bool, output_format string, calipers bool, enclosing bool) {
+	name := alg.Name()
 	time_total := int64(0)
-	points_copy := make([][2]float32, len(points))
+	points_copy := make([][2]T, len(points))
+
+	var stats hull.Stats
+	opts.Stats = &stats
 
 	for i := 0; i < trials; i++ {
 		// Copy because graham's will modify - next run will be O(N^2) quicksort otherwise
 		copy(points_copy, points)
 
 		fmt.Println()
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
 		fn_start := time.Now()
-		hull := method(points_copy)
+		result, err := alg.Hull(ctx, points_copy, opts)
 		elapsed := time.Since(fn_start)
-		fmt.Println(fmt.Sprintf("%s points on hull:", name), len(hull))
+		cancel()
+		if err != nil {
+			fmt.Println(name, "failed after", elapsed, err)
+			return
+		}
+		if opts.Prefilter != hull.PrefilterNone {
+			fmt.Printf("%s prefilter eliminated %d of %d points\n", name, stats.Eliminated, len(points))
+		}
+		fmt.Println(fmt.Sprintf("%s points on hull:", name), len(result))
 		fmt.Println(name, elapsed)
 
-		ns_elap := time.Since(fn_start).Nanoseconds()
-		time_total += (ns_elap)
+		time_total += elapsed.Nanoseconds()
 		// Write hull to output
 		if do_output {
-			output_points(fmt.Sprintf("%s.txt", name), hull)
+			if err := write_points(name, output_format, result); err != nil {
+				log.Fatal(err)
+			}
+		}
+		// After the time is recorded, so the measures don't count as hull time
+		if calipers {
+			print_calipers(name, result)
+		}
+		if enclosing {
+			print_enclosing(name, result)
 		}
 	}
 	avg_time := float64(time_total) / float64(trials)
@@ -43,10 +89,439 @@ func run_hull(points [][2]float32, method func([][2]float32) [][2]float32, name
 	}
 }
 
+// Print rotating-calipers measures of a hull, with the hull vertices they are supported by
+func print_calipers[T hull.Coord](name string, result [][2]T) {
+	c, err := hull.RotatingCalipers(result)
+	if err != nil {
+		fmt.Println(name, "calipers:", err)
+		return
+	}
+	fmt.Printf("%s diameter: %g between vertices %d and %d\n", name, c.Diameter, c.DiameterPair[0], c.DiameterPair[1])
+	fmt.Printf("%s width: %g from edge %d-%d to vertex %d\n", name, c.Width, c.WidthEdge[0], c.WidthEdge[1], c.WidthVertex)
+	for _, r := range []struct {
+		kind string
+		rect hull.Rectangle
+	}{{"min area", c.MinArea}, {"min perimeter", c.MinPerimeter}} {
+		fmt.Printf("%s %s rectangle: area %g perimeter %g on edge %d-%d, supported by vertices %v, corners %v\n",
+			name, r.kind, r.rect.Area, r.rect.Perimeter, r.rect.Edge[0], r.rect.Edge[1], r.rect.Support, r.rect.Corners)
+	}
+}
+
+// Print the smallest enclosing circle and ellipse, computed from the hull vertices
+func print_enclosing[T hull.Coord](name string, result [][2]T) {
+	start := time.Now()
+	c, err := hull.SmallestCircle(result)
+	if err != nil {
+		fmt.Println(name, "enclosing circle:", err)
+		return
+	}
+	fmt.Printf("%s enclosing circle: center %v radius %g on vertices %v (%v)\n", name, c.Center, c.Radius, c.Support, time.Since(start))
+	start = time.Now()
+	e, err := hull.SmallestEllipse(result, 0)
+	if err != nil {
+		fmt.Println(name, "enclosing ellipse:", err)
+		return
+	}
+	fmt.Printf("%s enclosing ellipse: center %v axes %v angle %g (%v)\n", name, e.Center, e.Axes, e.Angle, time.Since(start))
+}
+
+// Input file with its format resolved
+type input struct {
+	name string
+	// "text" or "binary"
+	format string
+	coord  hull.CoordType
+	// Point dimension, 2 to hull.MaxDimension
+	dim int
+	// Buffered stdin, so the sniffed header can still be read
+	stdin *bufio.Reader
+}
+
+// Work out the format of input ("auto" checks for the binary magic), its coordinate type and
+// dimension, which come from the header of binary files and from coord and dim for text
+func open_input(name, format, coord string, dim int) (input, error) {
+	in := input{name: name, format: format, dim: dim}
+	if format != "auto" && format != "text" && format != "binary" {
+		return in, fmt.Errorf("unknown input format %q", format)
+	}
+	if dim < 2 || dim > hull.MaxDimension {
+		return in, fmt.Errorf("unsupported dimension %d", dim)
+	}
+	coord_type, err := parse_coord_type(coord)
+	if err != nil {
+		return in, err
+	}
+	in.coord = coord_type
+
+	// Peek at the header
+	var header []byte
+	if name == "-" {
+		in.stdin = bufio.NewReader(os.Stdin)
+		header, _ = in.stdin.Peek(16)
+	} else if format != "text" {
+		f, err := os.Open(name)
+		if err != nil {
+			return in, err
+		}
+		header = make([]byte, 16)
+		n, _ := io.ReadFull(f, header)
+		header = header[:n]
+		f.Close()
+	}
+
+	if format == "auto" {
+		in.format = "text"
+		if strings.HasPrefix(string(header), hull.BinaryMagic) {
+			in.format = "binary"
+		}
+	}
+	if in.format == "binary" {
+		h, err := hull.ParseBinaryHeader(header)
+		if err != nil {
+			return in, fmt.Errorf("%s: %w", name, err)
+		}
+		in.coord = h.Coord
+		in.dim = int(h.Dimension)
+	}
+	return in, nil
+}
+
+// Coordinate type named s
+func parse_coord_type(s string) (hull.CoordType, error) {
+	for _, c := range []hull.CoordType{hull.CoordFloat32, hull.CoordFloat64, hull.CoordInt32, hull.CoordInt64} {
+		if c.String() == s {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown coordinate type %q", s)
+}
+
+// Prefilter named s
+func parse_prefilter(s string) (hull.Prefilter, error) {
+	for _, f := range []hull.Prefilter{hull.PrefilterNone, hull.PrefilterQuad, hull.PrefilterOctagon} {
+		if f.String() == s {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown prefilter %q", s)
+}
+
+// Read input points. Call close once done with the points (binary files may be memory mapped).
+func load_points[T hull.Coord](in input, skip_invalid bool) (points [][2]T, skipped int, close func() error, err error) {
+	close = func() error { return nil }
+	parse_opts := hull.ParseOptions{SkipInvalid: skip_invalid}
+
+	if in.stdin != nil {
+		if in.format == "binary" {
+			points, err = hull.ReadBinary[T](in.stdin)
+			return points, 0, close, err
+		}
+		points, skipped, err = hull.ReadPoints[T](in.stdin, "stdin", parse_opts)
+		return points, skipped, close, err
+	}
+
+	if in.format == "binary" {
+		file, err := hull.OpenBinary[T](in.name)
+		if err != nil {
+			return nil, 0, close, err
+		}
+		return file.Points, 0, file.Close, nil
+	}
+	points, skipped, err = hull.ParseFile[T](in.name, parse_opts)
+	return points, skipped, close, err
+}
+
+// Load the input as T coordinates and run every selected algorithm on it
+func run_all[T hull.Coord](in input, cfg run_config) error {
+	algs, err := hull.Resolve[T](cfg.algs)
+	if err != nil {
+		return err
+	}
+
+	points, skipped, close_input, err := load_points[T](in, cfg.skip_invalid)
+	if err != nil {
+		return err
+	}
+	defer close_input()
+	if skipped > 0 {
+		fmt.Printf("skipped %d invalid lines in %s\n", skipped, in.name)
+	}
+	if cfg.convert != "" {
+		return write_points(cfg.convert, cfg.output, points)
+	}
+	if len(points) == 0 {
+		fmt.Printf("file: %s has no points!\n", in.name)
+		return nil
+	}
+
+	save_time := (cfg.result_file != "")
+	for _, alg := range algs {
+		run_hull(points, alg, cfg.opts, cfg.timeout, cfg.trials, save_time, cfg.result_file, cfg.voi, cfg.do_output, cfg.output, cfg.calipers, cfg.enclosing)
+	}
+	return nil
+}
+
+// Read 3D input points. Call close once done with the points (binary files may be memory mapped).
+func load_points3d[T hull.Coord](in input, skip_invalid bool) (points [][3]T, skipped int, close func() error, err error) {
+	close = func() error { return nil }
+	parse_opts := hull.ParseOptions{SkipInvalid: skip_invalid}
+
+	if in.stdin != nil {
+		if in.format == "binary" {
+			points, err = hull.ReadBinary3D[T](in.stdin)
+			return points, 0, close, err
+		}
+		points, skipped, err = hull.ReadPoints3D[T](in.stdin, "stdin", parse_opts)
+		return points, skipped, close, err
+	}
+
+	if in.format == "binary" {
+		file, err := hull.OpenBinary3D[T](in.name)
+		if err != nil {
+			return nil, 0, close, err
+		}
+		return file.Points, 0, file.Close, nil
+	}
+	points, skipped, err = hull.ParseFile3D[T](in.name, parse_opts)
+	return points, skipped, close, err
+}
+
+// Hull algorithm for 3D or d-dimensional points, F is its function
+type named_hull[F any] struct {
+	name string
+	hull F
+}
+
+// Algorithms of all selected by algs (comma-separated names, all if empty), kind names them in errors
+func select_hulls[F any](all []named_hull[F], algs, kind string) ([]named_hull[F], error) {
+	if algs == "" {
+		return all, nil
+	}
+	names := make([]string, len(all))
+	for i, alg := range all {
+		names[i] = alg.name
+	}
+	var selected []named_hull[F]
+	for _, name := range strings.Split(algs, ",") {
+		found := false
+		for _, alg := range all {
+			if alg.name == strings.TrimSpace(name) {
+				selected = append(selected, alg)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown %s algorithm %q (available: %s)", kind, name, strings.Join(names, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// Run hull cfg.trials times, timing each run and passing its result to report, and save the
+// average time if cfg.result_file is set
+func time_trials[R any](name string, cfg run_config, hull func(ctx context.Context) (R, error), report func(result R) error) error {
+	time_total := int64(0)
+	for i := 0; i < cfg.trials; i++ {
+		fmt.Println()
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if cfg.timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		}
+		fn_start := time.Now()
+		result, err := hull(ctx)
+		elapsed := time.Since(fn_start)
+		cancel()
+		if err != nil {
+			fmt.Println(name, "failed after", elapsed, err)
+			break
+		}
+		if err := report(result); err != nil {
+			return err
+		}
+		fmt.Println(name, elapsed)
+		time_total += elapsed.Nanoseconds()
+	}
+	if cfg.result_file != "" {
+		fmt.Println("Saving at ", cfg.result_file)
+		f, _ := os.OpenFile(cfg.result_file, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+		fmt.Fprintf(f, "%s\t%d\t%f\t%s\n", name, cfg.trials, float64(time_total)/float64(cfg.trials), cfg.voi)
+		f.Close()
+	}
+	return nil
+}
+
+// Load the input as 3D points with T coordinates and run every selected 3D algorithm on it
+func run_all3d[T hull.Coord](in input, cfg run_config) error {
+	algs, err := select_hulls([]named_hull[func(context.Context, [][3]T) (*hull.Hull3D[T], error)]{
+		{"serial_qh3d", hull.Quickhull3DSerial[T]},
+		{"parallel_qh3d", hull.Quickhull3DParallel[T]},
+	}, cfg.algs, "3D")
+	if err != nil {
+		return err
+	}
+	points, skipped, close_input, err := load_points3d[T](in, cfg.skip_invalid)
+	if err != nil {
+		return err
+	}
+	defer close_input()
+	if skipped > 0 {
+		fmt.Printf("skipped %d invalid lines in %s\n", skipped, in.name)
+	}
+	if cfg.convert != "" {
+		switch cfg.output {
+		case "text":
+			return hull.OutputPoints3D(cfg.convert+".txt", points)
+		case "binary":
+			return hull.WriteBinaryFile3D(cfg.convert+".chpt", points)
+		}
+		return fmt.Errorf("unknown output format %q", cfg.output)
+	}
+	if len(points) == 0 {
+		fmt.Printf("file: %s has no points!\n", in.name)
+		return nil
+	}
+
+	for _, alg := range algs {
+		err := time_trials(alg.name, cfg, func(ctx context.Context) (*hull.Hull3D[T], error) {
+			return alg.hull(ctx, points)
+		}, func(result *hull.Hull3D[T]) error {
+			fmt.Printf("%s hull vertices: %d faces: %d\n", alg.name, len(result.Vertices), len(result.Faces))
+			// Faces need a mesh format, the hull is always written as OBJ
+			if cfg.do_output {
+				return hull.OutputOBJ(alg.name+".obj", result)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read d-dimensional input points. Call close once done with the points (binary files may be memory
+// mapped).
+func load_pointsd[T hull.Coord](in input, skip_invalid bool) (points [][]T, skipped int, close func() error, err error) {
+	close = func() error { return nil }
+	parse_opts := hull.ParseOptions{SkipInvalid: skip_invalid}
+
+	if in.stdin != nil {
+		if in.format == "binary" {
+			points, err = hull.ReadBinaryD[T](in.stdin, in.dim)
+			return points, 0, close, err
+		}
+		points, skipped, err = hull.ReadPointsD[T](in.stdin, "stdin", in.dim, parse_opts)
+		return points, skipped, close, err
+	}
+
+	if in.format == "binary" {
+		file, err := hull.OpenBinaryD[T](in.name, in.dim)
+		if err != nil {
+			return nil, 0, close, err
+		}
+		return file.Points, 0, file.Close, nil
+	}
+	points, skipped, err = hull.ParseFileD[T](in.name, in.dim, parse_opts)
+	return points, skipped, close, err
+}
+
+// Load the input as d-dimensional points with T coordinates and run every selected d-dimensional
+// algorithm on it
+func run_alld[T hull.Coord](in input, cfg run_config) error {
+	algs, err := select_hulls([]named_hull[func(context.Context, [][]T) (*hull.HullD, error)]{
+		{"serial_qhd", hull.QuickhullDSerial[T]},
+		{"parallel_qhd", hull.QuickhullDParallel[T]},
+	}, cfg.algs, fmt.Sprintf("%dD", in.dim))
+	if err != nil {
+		return err
+	}
+	points, skipped, close_input, err := load_pointsd[T](in, cfg.skip_invalid)
+	if err != nil {
+		return err
+	}
+	defer close_input()
+	if skipped > 0 {
+		fmt.Printf("skipped %d invalid lines in %s\n", skipped, in.name)
+	}
+	if cfg.convert != "" {
+		switch cfg.output {
+		case "text":
+			return hull.OutputPointsD(cfg.convert+".txt", in.dim, points)
+		case "binary":
+			return hull.WriteBinaryFileD(cfg.convert+".chpt", in.dim, points)
+		}
+		return fmt.Errorf("unknown output format %q", cfg.output)
+	}
+	if len(points) == 0 {
+		fmt.Printf("file: %s has no points!\n", in.name)
+		return nil
+	}
+
+	for _, alg := range algs {
+		err := time_trials(alg.name, cfg, func(ctx context.Context) (*hull.HullD, error) {
+			return alg.hull(ctx, points)
+		}, func(result *hull.HullD) error {
+			fmt.Printf("%s hull vertices: %d facets: %d\n", alg.name, len(result.Vertices), len(result.Facets))
+			// Facets are written as vertex index lists with their normals, whatever the output format
+			if cfg.do_output {
+				return hull.OutputHullD(alg.name+".txt", result)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write points to name.txt or name.chpt depending on format
+func write_points[T hull.Coord](name, format string, points [][2]T) error {
+	switch format {
+	case "text":
+		return hull.OutputPoints(name+".txt", points)
+	case "binary":
+		return hull.WriteBinaryFile(name+".chpt", points)
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// Human readable serial/parallel
+func parallel_string(parallel bool) string {
+	if parallel {
+		return "parallel"
+	}
+	return "serial"
+}
+
+// Human readable capability flags
+func caps_string(caps hull.Capability) string {
+	var flags []string
+	if caps.Has(hull.OrderedHull) {
+		flags = append(flags, "ordered")
+	}
+	if caps.Has(hull.KeepsCollinear) {
+		flags = append(flags, "collinear")
+	}
+	if caps.Has(hull.OutputSensitive) {
+		flags = append(flags, "output-sensitive")
+	}
+	if caps.Has(hull.ModifiesInput) {
+		flags = append(flags, "modifies-input")
+	}
+	return strings.Join(flags, ",")
+}
+
 func main() {
 
 	result_file_ptr := flag.String("result_file", "", "result file location")
-	inputPtr := flag.String("input", "./serial_quickhull/input_points.txt", "input file location")
+	inputPtr := flag.String("input", "./serial_quickhull/input_points.txt", "input file location (\"-\" for stdin)")
+	input_format := flag.String("input_format", "auto", "input format: text, binary or auto (detect binary files)")
+	output_format := flag.String("output_format", "text", "hull output format: text (name.txt) or binary (name.chpt)")
+	convert := flag.String("convert", "", "write the input points to this file (without extension) in -output_format and exit")
+	skip_invalid := flag.Bool("skip_invalid", false, "skip malformed input lines instead of failing")
+	enclosing := flag.Bool("enclosing", false, "print the smallest circle and ellipse enclosing each 2D hull")
+	calipers := flag.Bool("calipers", false, "print each 2D hull's diameter, width and smallest enclosing rectangles (vertex numbers count from 0 in hull order)")
 	num_trials_ptr := flag.Int("trials", 1, "number of trials")
 
 	// Pass something like number of points if you want it to be recorded in the data for later visualization
@@ -55,13 +530,27 @@ func main() {
 	go_maxprocs := flag.Int("procs", runtime.NumCPU(), "set runtime.GOMAXPROCS aka how many OS threads")
 	do_coalesce := flag.Bool("coalesce", false, "enable coalescing of subhulls from chan's iterations")
 	simul_iters := flag.Int("simul_iters", 2, "how many iterations of chan's to run simultaneously")
-	impl_ptr := flag.String("impl", "", "which implementation to run")
+	impl_ptr := flag.String("impl", "", "comma-separated algorithm names or families to run (default all, see -list)")
+	list_ptr := flag.Bool("list", false, "list registered algorithms and exit")
+	clockwise := flag.Bool("clockwise", false, "output hull clockwise instead of counterclockwise")
+	timeout := flag.Duration("timeout", 0, "give up on each hull after this long (0 for no limit)")
+	keep_collinear := flag.Bool("keep_collinear", false, "output every point on the hull boundary, not just the extreme vertices")
+	prefilter := flag.String("prefilter", "none", "discard points strictly inside the extreme points' polygon first: none, quad or octagon")
+	coord := flag.String("coord", "float32", "coordinate type of text input: float32, float64, int32 or int64 (binary input uses its header)")
+	dim := flag.Int("dim", 2, "dimension of text input: 2 (\"x,y\" lines), 3 (\"x,y,z\" lines, runs serial_qh3d and parallel_qh3d and writes name.obj meshes) or 4 to 8 (runs serial_qhd and parallel_qhd and writes name.txt facet lists)")
 
 	cpuprofile := flag.String("cpuprofile", "", "write cpu profile to file")
 	memprofile := flag.String("memprofile", "", "write memory profile to file")
 
 	flag.Parse()
 
+	if *list_ptr {
+		for _, alg := range hull.Algorithms[float32]() {
+			fmt.Printf("%-18s %-5s %-9s %-11s %s\n", alg.Name(), alg.Family(), parallel_string(alg.Parallel()), alg.Complexity(), caps_string(alg.Capabilities()))
+		}
+		return
+	}
+
 	// CPU/mem profiling w/ pproc - https://blog.golang.org/pprof
 	if *cpuprofile != "" {
 		f, err := os.Create(*cpuprofile)
@@ -74,44 +563,67 @@ func main() {
 
 	// Set # OS threads
 	runtime.GOMAXPROCS(*go_maxprocs)
-	// Enable coalescing of subhulls thru iterations
-	USE_COALESCE = *do_coalesce
-	// Set number of iterations of chan's to run simultaneously
-	SIMUL_ITERS = *simul_iters
-
-	do_output := *do_output_ptr
-	impl := *impl_ptr
-
-	save_time := (*result_file_ptr != "")
-	points := parse_file(*inputPtr)
 
-	if len(points) == 0 {
-		fmt.Printf("file: %s not found!\n", *inputPtr)
-		return
+	in, err := open_input(*inputPtr, *input_format, *coord, *dim)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	// Run jarvis march
-	if impl == "" || impl == "jarv" {
-		run_hull(points, seq_jarvis, "serial_jarvis", *num_trials_ptr, save_time, *result_file_ptr, *variable_of_interest, do_output)
-		run_hull(points, parallel_jarvis, "parallel_jarvis", *num_trials_ptr, save_time, *result_file_ptr, *variable_of_interest, do_output)
+	cfg := run_config{
+		algs:         *impl_ptr,
+		timeout:      *timeout,
+		trials:       *num_trials_ptr,
+		result_file:  *result_file_ptr,
+		voi:          *variable_of_interest,
+		do_output:    *do_output_ptr,
+		output:       *output_format,
+		convert:      *convert,
+		skip_invalid: *skip_invalid,
+		calipers:     *calipers,
+		enclosing:    *enclosing,
 	}
-
-	// Run graham scan
-	if impl == "" || impl == "grah" {
-		run_hull(points, seq_graham_scan, "serial_graham", *num_trials_ptr, save_time, *result_file_ptr, *variable_of_interest, do_output)
-		run_hull(points, parallel_graham_scan, "parallel_graham", *num_trials_ptr, save_time, *result_file_ptr, *variable_of_interest, do_output)
+	cfg.opts = hull.Options{
+		Clockwise: *clockwise,
+		// Set number of iterations of chan's to run simultaneously
+		SimulIters: *simul_iters,
+		// Enable coalescing of subhulls thru iterations
+		Coalesce: *do_coalesce,
 	}
-
-	// Run chan's
-	if impl == "" || impl == "chan" {
-		run_hull(points, seq_chans, "serial_chans", *num_trials_ptr, save_time, *result_file_ptr, *variable_of_interest, do_output)
-		run_hull(points, parallel_chans, "parallel_chans", *num_trials_ptr, save_time, *result_file_ptr, *variable_of_interest, do_output)
+	if *keep_collinear {
+		cfg.opts.Collinear = hull.CollinearInclusive
+	}
+	if cfg.opts.Prefilter, err = parse_prefilter(*prefilter); err != nil {
+		log.Fatal(err)
 	}
 
-	// Run quickhull
-	if impl == "" || impl == "quic" {
-		run_hull(points, quickhull_serial, "serial_qh", *num_trials_ptr, save_time, *result_file_ptr, *variable_of_interest, do_output)
-		run_hull(points, quickhull_parallel, "parallel_qh", *num_trials_ptr, save_time, *result_file_ptr, *variable_of_interest, do_output)
+	switch {
+	case in.dim > 3 && in.coord == hull.CoordFloat32:
+		err = run_alld[float32](in, cfg)
+	case in.dim > 3 && in.coord == hull.CoordFloat64:
+		err = run_alld[float64](in, cfg)
+	case in.dim > 3 && in.coord == hull.CoordInt32:
+		err = run_alld[int32](in, cfg)
+	case in.dim > 3 && in.coord == hull.CoordInt64:
+		err = run_alld[int64](in, cfg)
+	case in.dim == 3 && in.coord == hull.CoordFloat32:
+		err = run_all3d[float32](in, cfg)
+	case in.dim == 3 && in.coord == hull.CoordFloat64:
+		err = run_all3d[float64](in, cfg)
+	case in.dim == 3 && in.coord == hull.CoordInt32:
+		err = run_all3d[int32](in, cfg)
+	case in.dim == 3 && in.coord == hull.CoordInt64:
+		err = run_all3d[int64](in, cfg)
+	case in.coord == hull.CoordFloat32:
+		err = run_all[float32](in, cfg)
+	case in.coord == hull.CoordFloat64:
+		err = run_all[float64](in, cfg)
+	case in.coord == hull.CoordInt32:
+		err = run_all[int32](in, cfg)
+	case in.coord == hull.CoordInt64:
+		err = run_all[int64](in, cfg)
+	}
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	if *memprofile != "" {